@@ -3,10 +3,13 @@
 package tgbotapi
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"mime/multipart"
 	"net/http"
 	"net/url"
@@ -29,7 +32,31 @@ type BotAPI struct {
 	Client          HTTPClient `json:"-"`
 	shutdownChannel chan interface{}
 
-	apiEndpoint string
+	// Retry controls how failed requests are retried. Requests are not
+	// retried if it is nil.
+	Retry *RetryPolicy `json:"-"`
+	// Limiter throttles requests before they are sent. Requests are not
+	// throttled if it is nil.
+	Limiter RateLimiter `json:"-"`
+	// Encoding is how params are sent for requests without files to upload.
+	Encoding Encoding `json:"-"`
+	// Metrics receives measurements about requests and updates. Nothing is
+	// measured if it is nil.
+	Metrics Metrics `json:"-"`
+	// Tracer starts spans for updates and requests. Nothing is traced if it
+	// is nil.
+	Tracer Tracer `json:"-"`
+	// MaxDownloadSize is the size of the largest file DownloadFile and
+	// DownloadToPath download. It defaults to DefaultMaxDownloadSize if 0,
+	// and there is no limit if it is negative.
+	MaxDownloadSize int64 `json:"-"`
+
+	apiEndpoint  string
+	fileEndpoint string
+	logger       *slog.Logger
+	middleware   []RequestMiddleware
+	migrations   *ChatMigrations
+	dryRun       DryRunFunc
 }
 
 // NewBotAPI creates a new BotAPI instance.
@@ -61,13 +88,10 @@ func NewBotAPIWithClient(token, apiEndpoint string, client HTTPClient) (*BotAPI,
 		apiEndpoint: apiEndpoint,
 	}
 
-	self, err := bot.GetMe()
-	if err != nil {
+	if err := bot.Init(context.Background()); err != nil {
 		return nil, err
 	}
 
-	bot.Self = self
-
 	return bot, nil
 }
 
@@ -92,36 +116,126 @@ func buildParams(in Params) url.Values {
 
 // MakeRequest makes a request to a specific endpoint with our token.
 func (bot *BotAPI) MakeRequest(endpoint string, params Params) (*APIResponse, error) {
-	if bot.Debug {
-		log.Printf("Endpoint: %s, params: %v\n", endpoint, params)
-	}
+	return bot.MakeRequestWithContext(context.Background(), endpoint, params)
+}
 
-	method := fmt.Sprintf(bot.apiEndpoint, bot.Token, endpoint)
+// MakeRequestWithContext is like MakeRequest but uses ctx for the request.
+//
+// If the bot has a RetryPolicy, failed requests are retried according to it.
+func (bot *BotAPI) MakeRequestWithContext(ctx context.Context, endpoint string, params Params) (*APIResponse, error) {
+	return bot.handle(ctx, &APIRequest{Endpoint: endpoint, Params: params})
+}
 
-	values := buildParams(params)
+// makeRequest performs a single request to the API, with params encoded
+// according to the bot's Encoding and the JSON types of the params.
+func (bot *BotAPI) makeRequest(ctx context.Context, endpoint string, params Params, types paramTypes) (*APIResponse, error) {
+	if bot.Limiter != nil {
+		if err := bot.Limiter.Wait(ctx, endpoint, params); err != nil {
+			return nil, err
+		}
+	}
 
-	req, err := http.NewRequest("POST", method, strings.NewReader(values.Encode()))
+	body, contentType, err := bot.Encoding.encode(params, types)
 	if err != nil {
 		return &APIResponse{}, err
 	}
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return bot.send(ctx, endpoint, params, 0, bytes.NewReader(body), contentType, int64(len(body)))
+}
+
+// send posts body, of the given length or -1 if unknown, to the endpoint and
+// reads the response, logging the request and its outcome. The token is
+// redacted from returned errors.
+func (bot *BotAPI) send(ctx context.Context, endpoint string, params Params, files int, body io.Reader, contentType string, length int64) (*APIResponse, error) {
+	ctx, span := bot.startSpan(ctx, "telegram.attempt", slog.String("telegram.method", endpoint))
+	start := time.Now()
+
+	var counter *countingReader
+	if files > 0 {
+		counter = &countingReader{r: body}
+		body = counter
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf(bot.apiEndpoint, bot.Token, endpoint), body)
+	if err != nil {
+		return &APIResponse{}, bot.redactError(err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	if length > 0 {
+		req.ContentLength = length
+	}
+
+	var apiResp *APIResponse
 
 	resp, err := bot.Client.Do(req)
+	if err == nil {
+		apiResp, err = bot.readResponse(resp)
+		resp.Body.Close()
+	}
+	err = bot.redactError(err)
+	latency := time.Since(start)
+
+	var uploaded int64
+	if counter != nil {
+		uploaded = counter.n
+	}
+	bot.observeRequest(endpoint, latency, uploaded, err)
+	endSpan(span, err)
+
+	if bot.debugEnabled(ctx) {
+		bot.logRequest(ctx, endpoint, params, files, latency, apiResp, err)
+	}
+
+	return apiResp, err
+}
+
+// logRequest logs a request made to endpoint and its outcome at the debug
+// level.
+func (bot *BotAPI) logRequest(ctx context.Context, endpoint string, params Params, files int, latency time.Duration, resp *APIResponse, err error) {
+	attrs := []slog.Attr{slog.String("method", endpoint)}
+
+	if chatID, ok := params["chat_id"]; ok {
+		attrs = append(attrs, slog.String("chat_id", chatID))
+	}
+
+	attrs = append(attrs, slog.Duration("latency", latency))
+
+	attrs = append(attrs, slog.Any("params", bot.redactParams(params)))
+
+	if files > 0 {
+		attrs = append(attrs, slog.Int("files", files))
+	}
+
 	if err != nil {
-		return nil, err
+		var apiErr *Error
+		if errors.As(err, &apiErr) {
+			attrs = append(attrs, slog.Int("error_code", apiErr.Code))
+		}
+		attrs = append(attrs, slog.String("error", err.Error()))
+
+		bot.log(ctx, slog.LevelDebug, "request failed", attrs...)
+		return
+	}
+
+	if resp != nil {
+		attrs = append(attrs, slog.String("result", string(resp.Result)))
 	}
-	defer resp.Body.Close()
 
+	bot.log(ctx, slog.LevelDebug, "request", attrs...)
+}
+
+// readResponse decodes an APIResponse from resp. If Telegram reports that the
+// request failed, the returned error is an *Error.
+func (bot *BotAPI) readResponse(resp *http.Response) (*APIResponse, error) {
 	var apiResp APIResponse
-	bytes, err := bot.decodeAPIResponse(resp.Body, &apiResp)
+	err := json.NewDecoder(resp.Body).Decode(&apiResp)
 	if err != nil {
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return &apiResp, &Error{Code: resp.StatusCode, Message: resp.Status}
+		}
 		return &apiResp, err
 	}
 
-	if bot.Debug {
-		log.Printf("Endpoint: %s, response: %s\n", endpoint, string(bytes))
-	}
-
 	if !apiResp.Ok {
 		var parameters ResponseParameters
 
@@ -139,34 +253,72 @@ func (bot *BotAPI) MakeRequest(endpoint string, params Params) (*APIResponse, er
 	return &apiResp, nil
 }
 
-// decodeAPIResponse decode response and return slice of bytes if debug enabled.
-// If debug disabled, just decode http.Response.Body stream to APIResponse struct
-// for efficient memory usage
-func (bot *BotAPI) decodeAPIResponse(responseBody io.Reader, resp *APIResponse) ([]byte, error) {
-	if !bot.Debug {
-		dec := json.NewDecoder(responseBody)
-		err := dec.Decode(resp)
-		return nil, err
+// contextReader is an io.Reader that stops reading once its context is done.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (cr contextReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
 	}
 
-	// if debug, read response body
-	data, err := io.ReadAll(responseBody)
-	if err != nil {
-		return nil, err
+	return cr.r.Read(p)
+}
+
+// UploadFiles makes a request to the API with files.
+func (bot *BotAPI) UploadFiles(endpoint string, params Params, files []RequestFile) (*APIResponse, error) {
+	return bot.UploadFilesWithContext(context.Background(), endpoint, params, files)
+}
+
+// UploadFilesWithContext is like UploadFiles but uses ctx for the request.
+//
+// Cancelling ctx aborts the upload, including any file that is still being
+// streamed to Telegram. Uploads are retried by the bot's RetryPolicy only if
+// every file to upload is a ReplayableFileData, as other file data can only be
+// read once.
+func (bot *BotAPI) UploadFilesWithContext(ctx context.Context, endpoint string, params Params, files []RequestFile) (*APIResponse, error) {
+	return bot.handle(ctx, &APIRequest{Endpoint: endpoint, Params: params, Files: files})
+}
+
+// uploadFiles performs a multipart request to the API, retrying it if every
+// file can be uploaded again.
+func (bot *BotAPI) uploadFiles(ctx context.Context, endpoint string, params Params, files []RequestFile) (*APIResponse, error) {
+	// The boundary is kept across attempts so the length of the body stays
+	// the same.
+	boundary := multipart.NewWriter(io.Discard).Boundary()
+	length := multipartLength(params, files, boundary)
+
+	upload := func(ctx context.Context) (*APIResponse, error) {
+		if bot.Limiter != nil {
+			if err := bot.Limiter.Wait(ctx, endpoint, params); err != nil {
+				return nil, err
+			}
+		}
+
+		body, contentType := multipartBody(ctx, endpoint, params, files, boundary)
+		// Closing the reader unblocks the writing goroutine if the request
+		// fails before the whole body has been consumed.
+		defer body.Close()
+
+		return bot.send(ctx, endpoint, params, len(files), body, contentType, length)
 	}
 
-	err = json.Unmarshal(data, resp)
-	if err != nil {
-		return nil, err
+	if !replayableFiles(files) {
+		return upload(ctx)
 	}
 
-	return data, nil
+	return bot.withRetry(ctx, endpoint, upload)
 }
 
-// UploadFiles makes a request to the API with files.
-func (bot *BotAPI) UploadFiles(endpoint string, params Params, files []RequestFile) (*APIResponse, error) {
+// multipartBody returns a reader streaming the multipart body of a request
+// with params and files, and its content type.
+func multipartBody(ctx context.Context, endpoint string, params Params, files []RequestFile, boundary string) (io.ReadCloser, string) {
 	r, w := io.Pipe()
 	m := multipart.NewWriter(w)
+	_ = m.SetBoundary(boundary)
+	progress := uploadProgressFromContext(ctx)
 
 	// This code modified from the very helpful @HirbodBehnam
 	// https://github.com/go-telegram-bot-api/telegram-bot-api/issues/354#issuecomment-663856473
@@ -183,29 +335,10 @@ func (bot *BotAPI) UploadFiles(endpoint string, params Params, files []RequestFi
 
 		for _, file := range files {
 			if file.Data.NeedsUpload() {
-				name, reader, err := file.Data.UploadData()
-				if err != nil {
-					w.CloseWithError(err)
-					return
-				}
-
-				part, err := m.CreateFormFile(file.Name, name)
-				if err != nil {
+				if err := writeFilePart(ctx, m, endpoint, file, progress); err != nil {
 					w.CloseWithError(err)
 					return
 				}
-
-				if _, err := io.Copy(part, reader); err != nil {
-					w.CloseWithError(err)
-					return
-				}
-
-				if closer, ok := reader.(io.ReadCloser); ok {
-					if err = closer.Close(); err != nil {
-						w.CloseWithError(err)
-						return
-					}
-				}
 			} else {
 				value := file.Data.SendData()
 
@@ -217,71 +350,137 @@ func (bot *BotAPI) UploadFiles(endpoint string, params Params, files []RequestFi
 		}
 	}()
 
-	if bot.Debug {
-		log.Printf("Endpoint: %s, params: %v, with %d files\n", endpoint, params, len(files))
-	}
-
-	method := fmt.Sprintf(bot.apiEndpoint, bot.Token, endpoint)
+	return r, m.FormDataContentType()
+}
 
-	req, err := http.NewRequest("POST", method, r)
+// writeFilePart writes the part of a file to upload to m, reporting its
+// progress to progress if not nil. The file's reader is closed even if
+// writing it fails.
+func writeFilePart(ctx context.Context, m *multipart.Writer, endpoint string, file RequestFile, progress UploadProgressFunc) (err error) {
+	name, reader, err := file.Data.UploadData()
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	req.Header.Set("Content-Type", m.FormDataContentType())
+	if closer, ok := reader.(io.Closer); ok {
+		defer func() {
+			if closeErr := closer.Close(); err == nil {
+				err = closeErr
+			}
+		}()
+	}
 
-	resp, err := bot.Client.Do(req)
+	part, err := m.CreateFormFile(file.Name, name)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer resp.Body.Close()
 
-	var apiResp APIResponse
-	bytes, err := bot.decodeAPIResponse(resp.Body, &apiResp)
-	if err != nil {
-		return &apiResp, err
+	if progress != nil {
+		p := UploadProgress{Method: endpoint, Field: file.Name, FileName: name, Total: uploadSize(file.Data, reader)}
+		progress(p)
+		part = &progressWriter{w: part, fn: progress, progress: p}
 	}
 
-	if bot.Debug {
-		log.Printf("Endpoint: %s, response: %s\n", endpoint, string(bytes))
+	_, err = io.Copy(part, contextReader{ctx, reader})
+	return err
+}
+
+// multipartLength returns the length of the multipart body of a request with
+// params and files, or -1 if the size of a file to upload isn't known.
+func multipartLength(params Params, files []RequestFile, boundary string) int64 {
+	var sizes int64
+	counter := &countingWriter{}
+	m := multipart.NewWriter(counter)
+	_ = m.SetBoundary(boundary)
+
+	for field, value := range params {
+		_ = m.WriteField(field, value)
 	}
 
-	if !apiResp.Ok {
-		var parameters ResponseParameters
+	for _, file := range files {
+		if !file.Data.NeedsUpload() {
+			_ = m.WriteField(file.Name, file.Data.SendData())
+			continue
+		}
 
-		if apiResp.Parameters != nil {
-			parameters = *apiResp.Parameters
+		replayable, ok := file.Data.(ReplayableFileData)
+		if !ok {
+			return -1
 		}
 
-		return &apiResp, &Error{
-			Message:            apiResp.Description,
-			ResponseParameters: parameters,
+		size := replayable.UploadSize()
+		if size < 0 {
+			return -1
 		}
+		sizes += size
+
+		name, reader, err := replayable.UploadData()
+		if err != nil {
+			return -1
+		}
+		if closer, ok := reader.(io.Closer); ok {
+			closer.Close()
+		}
+
+		_, _ = m.CreateFormFile(file.Name, name)
 	}
 
-	return &apiResp, nil
+	_ = m.Close()
+
+	return counter.n + sizes
+}
+
+// countingWriter counts the bytes written to it.
+type countingWriter struct {
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	cw.n += int64(len(p))
+	return len(p), nil
+}
+
+// replayableFiles reports whether every file to upload can be uploaded again.
+func replayableFiles(files []RequestFile) bool {
+	for _, file := range files {
+		if _, ok := file.Data.(ReplayableFileData); file.Data.NeedsUpload() && !ok {
+			return false
+		}
+	}
+
+	return true
 }
 
 // GetFileDirectURL returns direct URL to file
 //
 // It requires the FileID.
 func (bot *BotAPI) GetFileDirectURL(fileID string) (string, error) {
-	file, err := bot.GetFile(FileConfig{fileID})
+	return bot.GetFileDirectURLWithContext(context.Background(), fileID)
+}
+
+// GetFileDirectURLWithContext is like GetFileDirectURL but uses ctx for the request.
+func (bot *BotAPI) GetFileDirectURLWithContext(ctx context.Context, fileID string) (string, error) {
+	file, err := bot.GetFileWithContext(ctx, FileConfig{fileID})
 
 	if err != nil {
 		return "", err
 	}
 
-	return file.Link(bot.Token), nil
+	return fmt.Sprintf(bot.getFileEndpoint(), bot.Token, file.FilePath), nil
 }
 
 // GetMe fetches the currently authenticated bot.
 //
-// This method is called upon creation to validate the token,
+// This method is called by NewBotAPI and Init to validate the token,
 // and so you may get this data from BotAPI.Self without the need for
 // another request.
 func (bot *BotAPI) GetMe() (User, error) {
-	resp, err := bot.MakeRequest("getMe", nil)
+	return bot.GetMeWithContext(context.Background())
+}
+
+// GetMeWithContext is like GetMe but uses ctx for the request.
+func (bot *BotAPI) GetMeWithContext(ctx context.Context) (User, error) {
+	resp, err := bot.MakeRequestWithContext(ctx, "getMe", nil)
 	if err != nil {
 		return User{}, err
 	}
@@ -311,62 +510,76 @@ func hasFilesNeedingUpload(files []RequestFile) bool {
 
 // Request sends a Chattable to Telegram, and returns the APIResponse.
 func (bot *BotAPI) Request(c Chattable) (*APIResponse, error) {
+	return bot.RequestWithContext(context.Background(), c)
+}
+
+// RequestWithContext is like Request but uses ctx for the request.
+func (bot *BotAPI) RequestWithContext(ctx context.Context, c Chattable) (*APIResponse, error) {
+	req, err := bot.Render(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return bot.handle(ctx, req)
+}
+
+// Render returns the request that sending c makes, as seen by middleware,
+// without sending it. Params include the files that don't need to be uploaded,
+// and media to upload are referenced with attach:// in params.
+//
+// Files are only set when at least one file needs to be uploaded, in which
+// case the request is sent as a multipart body.
+func (bot *BotAPI) Render(c Chattable) (*APIRequest, error) {
 	params, err := c.params()
 	if err != nil {
 		return nil, err
 	}
+
+	req := &APIRequest{Endpoint: c.method(), Params: params.Params, types: params.types}
+
 	if t, ok := c.(Fileable); ok {
 		files := t.files()
 
-		// If we have files that need to be uploaded, we should delegate the
-		// request to UploadFile.
+		// If we have files that need to be uploaded, the request has to be
+		// sent as multipart.
 		if hasFilesNeedingUpload(files) {
-			return bot.UploadFiles(t.method(), params, files)
+			req.Files = files
+			return req, nil
 		}
 
 		// However, if there are no files to be uploaded, there's likely things
 		// that need to be turned into params instead.
+		if len(files) > 0 && req.Params == nil {
+			req.Params = make(Params)
+		}
 		for _, file := range files {
-			params[file.Name] = file.Data.SendData()
+			req.Params[file.Name] = file.Data.SendData()
+			delete(req.types, file.Name)
 		}
 	}
 
-	return bot.MakeRequest(c.method(), params)
+	return req, nil
 }
 
 // Send will send a Chattable item to Telegram and provides the
 // returned Message.
 func (bot *BotAPI) Send(c Chattable) (Message, error) {
-	resp, err := bot.Request(c)
-	if err != nil {
-		return Message{}, err
-	}
-
-	var message Message
-	err = json.Unmarshal(resp.Result, &message)
-	var unmarshalTypeError *json.UnmarshalTypeError
-	if errors.As(err, &unmarshalTypeError) {
-		// Telegram sometimes returns a boolean instead of a message
-		var ok bool
-		err = json.Unmarshal(resp.Result, &ok)
-		if err == nil && ok {
-			return Message{}, nil
-		}
-	}
-	return message, err
+	return bot.SendWithContext(context.Background(), c)
+}
+
+// SendWithContext is like Send but uses ctx for the request.
+func (bot *BotAPI) SendWithContext(ctx context.Context, c Chattable) (Message, error) {
+	return CallWithContext[Message](ctx, bot, c)
 }
 
 // SendMediaGroup sends a media group and returns the resulting messages.
 func (bot *BotAPI) SendMediaGroup(config MediaGroupConfig) ([]Message, error) {
-	resp, err := bot.Request(config)
-	if err != nil {
-		return nil, err
-	}
-
-	var messages []Message
-	err = json.Unmarshal(resp.Result, &messages)
+	return bot.SendMediaGroupWithContext(context.Background(), config)
+}
 
-	return messages, err
+// SendMediaGroupWithContext is like SendMediaGroup but uses ctx for the request.
+func (bot *BotAPI) SendMediaGroupWithContext(ctx context.Context, config MediaGroupConfig) ([]Message, error) {
+	return DoWithContext(ctx, bot, config)
 }
 
 // GetUserProfilePhotos gets a user's profile photos.
@@ -374,30 +587,24 @@ func (bot *BotAPI) SendMediaGroup(config MediaGroupConfig) ([]Message, error) {
 // It requires UserID.
 // Offset and Limit are optional.
 func (bot *BotAPI) GetUserProfilePhotos(config UserProfilePhotosConfig) (UserProfilePhotos, error) {
-	resp, err := bot.Request(config)
-	if err != nil {
-		return UserProfilePhotos{}, err
-	}
-
-	var profilePhotos UserProfilePhotos
-	err = json.Unmarshal(resp.Result, &profilePhotos)
+	return bot.GetUserProfilePhotosWithContext(context.Background(), config)
+}
 
-	return profilePhotos, err
+// GetUserProfilePhotosWithContext is like GetUserProfilePhotos but uses ctx for the request.
+func (bot *BotAPI) GetUserProfilePhotosWithContext(ctx context.Context, config UserProfilePhotosConfig) (UserProfilePhotos, error) {
+	return DoWithContext(ctx, bot, config)
 }
 
 // GetFile returns a File which can download a file from Telegram.
 //
 // Requires FileID.
 func (bot *BotAPI) GetFile(config FileConfig) (File, error) {
-	resp, err := bot.Request(config)
-	if err != nil {
-		return File{}, err
-	}
-
-	var file File
-	err = json.Unmarshal(resp.Result, &file)
+	return bot.GetFileWithContext(context.Background(), config)
+}
 
-	return file, err
+// GetFileWithContext is like GetFile but uses ctx for the request.
+func (bot *BotAPI) GetFileWithContext(ctx context.Context, config FileConfig) (File, error) {
+	return DoWithContext(ctx, bot, config)
 }
 
 // GetUpdates fetches updates.
@@ -408,49 +615,68 @@ func (bot *BotAPI) GetFile(config FileConfig) (File, error) {
 // Set Timeout to a large number to reduce requests, so you can get updates
 // instantly instead of having to wait between requests.
 func (bot *BotAPI) GetUpdates(config UpdateConfig) ([]Update, error) {
-	resp, err := bot.Request(config)
-	if err != nil {
-		return []Update{}, err
-	}
-
-	var updates []Update
-	err = json.Unmarshal(resp.Result, &updates)
+	return bot.GetUpdatesWithContext(context.Background(), config)
+}
 
-	return updates, err
+// GetUpdatesWithContext is like GetUpdates but uses ctx for the request.
+func (bot *BotAPI) GetUpdatesWithContext(ctx context.Context, config UpdateConfig) ([]Update, error) {
+	return DoWithContext(ctx, bot, config)
 }
 
 // GetWebhookInfo allows you to fetch information about a webhook and if
 // one currently is set, along with pending update count and error messages.
 func (bot *BotAPI) GetWebhookInfo() (WebhookInfo, error) {
-	resp, err := bot.MakeRequest("getWebhookInfo", nil)
-	if err != nil {
-		return WebhookInfo{}, err
-	}
-
-	var info WebhookInfo
-	err = json.Unmarshal(resp.Result, &info)
+	return bot.GetWebhookInfoWithContext(context.Background())
+}
 
-	return info, err
+// GetWebhookInfoWithContext is like GetWebhookInfo but uses ctx for the request.
+func (bot *BotAPI) GetWebhookInfoWithContext(ctx context.Context) (WebhookInfo, error) {
+	return DoWithContext(ctx, bot, WebhookInfo{})
 }
 
 // GetUpdatesChan starts and returns a channel for getting updates.
 func (bot *BotAPI) GetUpdatesChan(config UpdateConfig) UpdatesChannel {
+	return bot.GetUpdatesChanWithContext(context.Background(), config)
+}
+
+// GetUpdatesChanWithContext is like GetUpdatesChan but stops receiving
+// updates and closes the channel once ctx is done, in addition to
+// StopReceivingUpdates. In-flight getUpdates requests are cancelled with ctx.
+func (bot *BotAPI) GetUpdatesChanWithContext(ctx context.Context, config UpdateConfig) UpdatesChannel {
 	ch := make(chan Update, bot.Buffer)
+	bot.observeUpdateQueue(UpdateSourcePolling, ch)
 
+	ctx, cancel := context.WithCancel(ctx)
 	go func() {
+		select {
+		case <-bot.shutdownChannel:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	go func() {
+		defer cancel()
+		defer close(ch)
+
 		for {
-			select {
-			case <-bot.shutdownChannel:
-				close(ch)
+			if ctx.Err() != nil {
 				return
-			default:
 			}
 
-			updates, err := bot.GetUpdates(config)
+			updates, err := bot.GetUpdatesWithContext(ctx, config)
 			if err != nil {
-				log.Println(err)
-				log.Println("Failed to get updates, retrying in 3 seconds...")
-				time.Sleep(time.Second * 3)
+				if ctx.Err() != nil {
+					return
+				}
+
+				bot.log(ctx, slog.LevelWarn, "failed to get updates, retrying in 3 seconds", slog.String("error", err.Error()))
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(time.Second * 3):
+				}
 
 				continue
 			}
@@ -458,7 +684,13 @@ func (bot *BotAPI) GetUpdatesChan(config UpdateConfig) UpdatesChannel {
 			for _, update := range updates {
 				if update.UpdateID >= config.Offset {
 					config.Offset = update.UpdateID + 1
-					ch <- update
+					bot.observeUpdate(update)
+
+					select {
+					case ch <- update:
+					case <-ctx.Done():
+						return
+					}
 				}
 			}
 		}
@@ -469,15 +701,14 @@ func (bot *BotAPI) GetUpdatesChan(config UpdateConfig) UpdatesChannel {
 
 // StopReceivingUpdates stops the go routine which receives updates
 func (bot *BotAPI) StopReceivingUpdates() {
-	if bot.Debug {
-		log.Println("Stopping the update receiver routine...")
-	}
+	bot.log(context.Background(), slog.LevelDebug, "stopping the update receiver routine")
 	close(bot.shutdownChannel)
 }
 
 // ListenForWebhook registers a http handler for a webhook.
 func (bot *BotAPI) ListenForWebhook(pattern string) UpdatesChannel {
 	ch := make(chan Update, bot.Buffer)
+	bot.observeUpdateQueue(UpdateSourceWebhook, ch)
 
 	http.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
 		update, err := bot.HandleUpdate(r)
@@ -530,6 +761,8 @@ func (bot *BotAPI) HandleUpdate(r *http.Request) (*Update, error) {
 		return nil, err
 	}
 
+	bot.observeUpdate(update)
+
 	return &update, nil
 }
 
@@ -546,12 +779,17 @@ func WriteToHTTPResponse(w http.ResponseWriter, c Chattable) error {
 	}
 
 	if t, ok := c.(Fileable); ok {
-		if hasFilesNeedingUpload(t.files()) {
+		files := t.files()
+		if hasFilesNeedingUpload(files) {
 			return errors.New("unable to use http response to upload files")
 		}
+
+		for _, file := range files {
+			params.Params[file.Name] = file.Data.SendData()
+		}
 	}
 
-	values := buildParams(params)
+	values := buildParams(params.Params)
 	values.Set("method", c.method())
 
 	w.Header().Set("Content-Type", "application/x-www-form-urlencoded")
@@ -561,15 +799,12 @@ func WriteToHTTPResponse(w http.ResponseWriter, c Chattable) error {
 
 // GetChat gets information about a chat.
 func (bot *BotAPI) GetChat(config ChatInfoConfig) (Chat, error) {
-	resp, err := bot.Request(config)
-	if err != nil {
-		return Chat{}, err
-	}
-
-	var chat Chat
-	err = json.Unmarshal(resp.Result, &chat)
+	return bot.GetChatWithContext(context.Background(), config)
+}
 
-	return chat, err
+// GetChatWithContext is like GetChat but uses ctx for the request.
+func (bot *BotAPI) GetChatWithContext(ctx context.Context, config ChatInfoConfig) (Chat, error) {
+	return DoWithContext(ctx, bot, config)
 }
 
 // GetChatAdministrators gets a list of administrators in the chat.
@@ -577,93 +812,77 @@ func (bot *BotAPI) GetChat(config ChatInfoConfig) (Chat, error) {
 // If none have been appointed, only the creator will be returned.
 // Bots are not shown, even if they are an administrator.
 func (bot *BotAPI) GetChatAdministrators(config ChatAdministratorsConfig) ([]ChatMember, error) {
-	resp, err := bot.Request(config)
-	if err != nil {
-		return []ChatMember{}, err
-	}
-
-	var members []ChatMember
-	err = json.Unmarshal(resp.Result, &members)
+	return bot.GetChatAdministratorsWithContext(context.Background(), config)
+}
 
-	return members, err
+// GetChatAdministratorsWithContext is like GetChatAdministrators but uses ctx for the request.
+func (bot *BotAPI) GetChatAdministratorsWithContext(ctx context.Context, config ChatAdministratorsConfig) ([]ChatMember, error) {
+	return DoWithContext(ctx, bot, config)
 }
 
 // GetChatMembersCount gets the number of users in a chat.
 func (bot *BotAPI) GetChatMembersCount(config ChatMemberCountConfig) (int, error) {
-	resp, err := bot.Request(config)
+	return bot.GetChatMembersCountWithContext(context.Background(), config)
+}
+
+// GetChatMembersCountWithContext is like GetChatMembersCount but uses ctx for the request.
+func (bot *BotAPI) GetChatMembersCountWithContext(ctx context.Context, config ChatMemberCountConfig) (int, error) {
+	count, err := DoWithContext(ctx, bot, config)
 	if err != nil {
 		return -1, err
 	}
 
-	var count int
-	err = json.Unmarshal(resp.Result, &count)
-
-	return count, err
+	return count, nil
 }
 
 // GetChatMember gets a specific chat member.
 func (bot *BotAPI) GetChatMember(config GetChatMemberConfig) (ChatMember, error) {
-	resp, err := bot.Request(config)
-	if err != nil {
-		return ChatMember{}, err
-	}
-
-	var member ChatMember
-	err = json.Unmarshal(resp.Result, &member)
+	return bot.GetChatMemberWithContext(context.Background(), config)
+}
 
-	return member, err
+// GetChatMemberWithContext is like GetChatMember but uses ctx for the request.
+func (bot *BotAPI) GetChatMemberWithContext(ctx context.Context, config GetChatMemberConfig) (ChatMember, error) {
+	return DoWithContext(ctx, bot, config)
 }
 
 // GetGameHighScores allows you to get the high scores for a game.
 func (bot *BotAPI) GetGameHighScores(config GetGameHighScoresConfig) ([]GameHighScore, error) {
-	resp, err := bot.Request(config)
-	if err != nil {
-		return []GameHighScore{}, err
-	}
-
-	var highScores []GameHighScore
-	err = json.Unmarshal(resp.Result, &highScores)
+	return bot.GetGameHighScoresWithContext(context.Background(), config)
+}
 
-	return highScores, err
+// GetGameHighScoresWithContext is like GetGameHighScores but uses ctx for the request.
+func (bot *BotAPI) GetGameHighScoresWithContext(ctx context.Context, config GetGameHighScoresConfig) ([]GameHighScore, error) {
+	return DoWithContext(ctx, bot, config)
 }
 
 // GetInviteLink get InviteLink for a chat
 func (bot *BotAPI) GetInviteLink(config ChatInviteLinkConfig) (string, error) {
-	resp, err := bot.Request(config)
-	if err != nil {
-		return "", err
-	}
-
-	var inviteLink string
-	err = json.Unmarshal(resp.Result, &inviteLink)
+	return bot.GetInviteLinkWithContext(context.Background(), config)
+}
 
-	return inviteLink, err
+// GetInviteLinkWithContext is like GetInviteLink but uses ctx for the request.
+func (bot *BotAPI) GetInviteLinkWithContext(ctx context.Context, config ChatInviteLinkConfig) (string, error) {
+	return DoWithContext(ctx, bot, config)
 }
 
 // GetStickerSet returns a StickerSet.
 func (bot *BotAPI) GetStickerSet(config GetStickerSetConfig) (StickerSet, error) {
-	resp, err := bot.Request(config)
-	if err != nil {
-		return StickerSet{}, err
-	}
-
-	var stickers StickerSet
-	err = json.Unmarshal(resp.Result, &stickers)
+	return bot.GetStickerSetWithContext(context.Background(), config)
+}
 
-	return stickers, err
+// GetStickerSetWithContext is like GetStickerSet but uses ctx for the request.
+func (bot *BotAPI) GetStickerSetWithContext(ctx context.Context, config GetStickerSetConfig) (StickerSet, error) {
+	return DoWithContext(ctx, bot, config)
 }
 
 // StopPoll stops a poll and returns the result.
 func (bot *BotAPI) StopPoll(config StopPollConfig) (Poll, error) {
-	resp, err := bot.Request(config)
-	if err != nil {
-		return Poll{}, err
-	}
-
-	var poll Poll
-	err = json.Unmarshal(resp.Result, &poll)
+	return bot.StopPollWithContext(context.Background(), config)
+}
 
-	return poll, err
+// StopPollWithContext is like StopPoll but uses ctx for the request.
+func (bot *BotAPI) StopPollWithContext(ctx context.Context, config StopPollConfig) (Poll, error) {
+	return DoWithContext(ctx, bot, config)
 }
 
 // GetMyCommands gets the currently registered commands.
@@ -673,57 +892,45 @@ func (bot *BotAPI) GetMyCommands() ([]BotCommand, error) {
 
 // GetMyCommandsWithConfig gets the currently registered commands with a config.
 func (bot *BotAPI) GetMyCommandsWithConfig(config GetMyCommandsConfig) ([]BotCommand, error) {
-	resp, err := bot.Request(config)
-	if err != nil {
-		return nil, err
-	}
-
-	var commands []BotCommand
-	err = json.Unmarshal(resp.Result, &commands)
+	return bot.GetMyCommandsWithConfigAndContext(context.Background(), config)
+}
 
-	return commands, err
+// GetMyCommandsWithConfigAndContext is like GetMyCommandsWithConfig but uses ctx for the request.
+func (bot *BotAPI) GetMyCommandsWithConfigAndContext(ctx context.Context, config GetMyCommandsConfig) ([]BotCommand, error) {
+	return DoWithContext(ctx, bot, config)
 }
 
 // CopyMessage copy messages of any kind. The method is analogous to the method
 // forwardMessage, but the copied message doesn't have a link to the original
 // message. Returns the MessageID of the sent message on success.
 func (bot *BotAPI) CopyMessage(config CopyMessageConfig) (MessageID, error) {
-	resp, err := bot.Request(config)
-	if err != nil {
-		return MessageID{}, err
-	}
-
-	var messageID MessageID
-	err = json.Unmarshal(resp.Result, &messageID)
+	return bot.CopyMessageWithContext(context.Background(), config)
+}
 
-	return messageID, err
+// CopyMessageWithContext is like CopyMessage but uses ctx for the request.
+func (bot *BotAPI) CopyMessageWithContext(ctx context.Context, config CopyMessageConfig) (MessageID, error) {
+	return DoWithContext(ctx, bot, config)
 }
 
 // AnswerWebAppQuery sets the result of an interaction with a Web App and send a
 // corresponding message on behalf of the user to the chat from which the query originated.
 func (bot *BotAPI) AnswerWebAppQuery(config AnswerWebAppQueryConfig) (SentWebAppMessage, error) {
-	var sentWebAppMessage SentWebAppMessage
-
-	resp, err := bot.Request(config)
-	if err != nil {
-		return sentWebAppMessage, err
-	}
+	return bot.AnswerWebAppQueryWithContext(context.Background(), config)
+}
 
-	err = json.Unmarshal(resp.Result, &sentWebAppMessage)
-	return sentWebAppMessage, err
+// AnswerWebAppQueryWithContext is like AnswerWebAppQuery but uses ctx for the request.
+func (bot *BotAPI) AnswerWebAppQueryWithContext(ctx context.Context, config AnswerWebAppQueryConfig) (SentWebAppMessage, error) {
+	return DoWithContext(ctx, bot, config)
 }
 
 // GetMyDefaultAdministratorRights gets the current default administrator rights of the bot.
 func (bot *BotAPI) GetMyDefaultAdministratorRights(config GetMyDefaultAdministratorRightsConfig) (ChatAdministratorRights, error) {
-	var rights ChatAdministratorRights
-
-	resp, err := bot.Request(config)
-	if err != nil {
-		return rights, err
-	}
+	return bot.GetMyDefaultAdministratorRightsWithContext(context.Background(), config)
+}
 
-	err = json.Unmarshal(resp.Result, &rights)
-	return rights, err
+// GetMyDefaultAdministratorRightsWithContext is like GetMyDefaultAdministratorRights but uses ctx for the request.
+func (bot *BotAPI) GetMyDefaultAdministratorRightsWithContext(ctx context.Context, config GetMyDefaultAdministratorRightsConfig) (ChatAdministratorRights, error) {
+	return DoWithContext(ctx, bot, config)
 }
 
 // EscapeText takes an input text and escape Telegram markup symbols.
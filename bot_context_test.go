@@ -0,0 +1,139 @@
+package tgbotapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newLocalBot returns a bot that talks to a local test server instead of
+// Telegram. The handler receives every API call made by the bot.
+func newLocalBot(t *testing.T, handler http.HandlerFunc) *BotAPI {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	return &BotAPI{
+		Token:           "123456:local-test-token",
+		Client:          srv.Client(),
+		Buffer:          100,
+		shutdownChannel: make(chan interface{}),
+		apiEndpoint:     srv.URL + "/bot%s/%s",
+	}
+}
+
+func writeResult(w http.ResponseWriter, result string) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = io.WriteString(w, `{"ok":true,"result":`+result+`}`)
+}
+
+func TestMakeRequestWithContextCancelled(t *testing.T) {
+	bot := newLocalBot(t, func(w http.ResponseWriter, r *http.Request) {
+		writeResult(w, "true")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := bot.MakeRequestWithContext(ctx, "getMe", nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestMakeRequestWithContextDeadline(t *testing.T) {
+	bot := newLocalBot(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		<-r.Context().Done()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := bot.GetChatWithContext(ctx, ChatInfoConfig{ChatConfig{ChatID: 1}})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+type endlessReader struct{}
+
+func (endlessReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 'a'
+	}
+	return len(p), nil
+}
+
+func TestUploadFilesWithContextCancel(t *testing.T) {
+	started := make(chan struct{})
+	bot := newLocalBot(t, func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		_, _ = io.Copy(io.Discard, r.Body)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := bot.UploadFilesWithContext(ctx, "sendDocument", Params{"chat_id": "1"}, []RequestFile{{
+			Name: "document",
+			Data: FileReader{Name: "endless.txt", Reader: endlessReader{}},
+		}})
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("expected an error after cancelling the upload")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("upload was not cancelled")
+	}
+}
+
+func TestGetUpdatesChanWithContext(t *testing.T) {
+	bot := newLocalBot(t, func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/getUpdates") {
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+
+		if r.FormValue("offset") == "" {
+			writeResult(w, `[{"update_id":1,"message":{"message_id":1,"date":0,"chat":{"id":1,"type":"private"},"text":"hi"}}]`)
+			return
+		}
+
+		// Simulate long polling until the client goes away.
+		<-r.Context().Done()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	updates := bot.GetUpdatesChanWithContext(ctx, NewUpdate(0))
+
+	update := <-updates
+	if update.Message == nil || update.Message.Text != "hi" {
+		t.Fatalf("unexpected update %+v", update)
+	}
+
+	cancel()
+
+	select {
+	case _, ok := <-updates:
+		if ok {
+			t.Fatal("expected updates channel to be closed")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("updates channel was not closed after cancel")
+	}
+}
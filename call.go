@@ -0,0 +1,70 @@
+package tgbotapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+// Call sends c to Telegram and decodes the result as T.
+//
+// Telegram returns True instead of the result for some requests, such as edits
+// of inline messages. In that case the zero value of T is returned without an
+// error.
+func Call[T any](bot *BotAPI, c Chattable) (T, error) {
+	return CallWithContext[T](context.Background(), bot, c)
+}
+
+// CallWithContext is like Call but uses ctx for the request.
+func CallWithContext[T any](ctx context.Context, bot *BotAPI, c Chattable) (T, error) {
+	var result T
+
+	resp, err := bot.RequestWithContext(ctx, c)
+	if err != nil {
+		return result, err
+	}
+
+	err = decodeResult(resp.Result, &result)
+	return result, err
+}
+
+// Resultable is a Chattable that declares the type of its result. All configs
+// in this package implement it, except RawRequest, whose result depends on its
+// Method and is decoded with Call, and InputStickerConfig, which is only sent
+// as part of other configs.
+type Resultable[T any] interface {
+	Chattable
+	returns(*T)
+}
+
+// Do sends c to Telegram and returns its result, with a type checked at
+// compile time. For example, Do(bot, GetChatMemberConfig{...}) returns a
+// ChatMember and Do(bot, NewMessage(...)) returns a Message.
+//
+// Like Call, it returns the zero value of the result if Telegram returns True
+// instead.
+func Do[T any](bot *BotAPI, c Resultable[T]) (T, error) {
+	return CallWithContext[T](context.Background(), bot, c)
+}
+
+// DoWithContext is like Do but uses ctx for the request.
+func DoWithContext[T any](ctx context.Context, bot *BotAPI, c Resultable[T]) (T, error) {
+	return CallWithContext[T](ctx, bot, c)
+}
+
+// decodeResult decodes the result of a request into v. If the result is True
+// and can't be decoded into v, v is left untouched and no error is returned.
+func decodeResult(result json.RawMessage, v interface{}) error {
+	err := json.Unmarshal(result, v)
+
+	var unmarshalTypeError *json.UnmarshalTypeError
+	if errors.As(err, &unmarshalTypeError) {
+		// Telegram sometimes returns a boolean instead of the result
+		var ok bool
+		if json.Unmarshal(result, &ok) == nil && ok {
+			return nil
+		}
+	}
+
+	return err
+}
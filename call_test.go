@@ -0,0 +1,102 @@
+package tgbotapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDo(t *testing.T) {
+	bot := newLocalBot(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/createForumTopic"):
+			writeResult(w, `{"message_thread_id":5,"name":"`+r.FormValue("name")+`","icon_color":7322096}`)
+		case strings.HasSuffix(r.URL.Path, "/getAvailableGifts"):
+			writeResult(w, `{"gifts":[{"id":"1","sticker":{"file_id":"a"},"star_count":15}]}`)
+		case strings.HasSuffix(r.URL.Path, "/getWebhookInfo"):
+			writeResult(w, `{"url":"https://example.com/hook","pending_update_count":3}`)
+		case strings.HasSuffix(r.URL.Path, "/editMessageText"):
+			writeResult(w, `true`)
+		default:
+			writeResult(w, `true`)
+		}
+	})
+
+	topic, err := Do(bot, ForumTopicConfig{ForumTopic: ForumTopic{ChatID: -1, Name: "Topic"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if topic.MessageThreadID != 5 || topic.Name != "Topic" {
+		t.Errorf("unexpected topic %+v", topic)
+	}
+
+	gifts, err := Do(bot, GetAvailableGiftsConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gifts.Gifts) != 1 || gifts.Gifts[0].StarCount != 15 {
+		t.Errorf("unexpected gifts %+v", gifts)
+	}
+
+	info, err := Do(bot, WebhookInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.URL != "https://example.com/hook" || info.PendingUpdateCount != 3 {
+		t.Errorf("unexpected webhook info %+v", info)
+	}
+
+	// Editing an inline message returns True instead of a Message.
+	msg, err := Do(bot, EditMessageTextConfig{BaseEdit: BaseEdit{InlineMessageID: "inline"}, Text: "text"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.MessageID != 0 {
+		t.Errorf("expected an empty message, got %+v", msg)
+	}
+
+	ok, err := Do(bot, NewChatAction(1, ChatTyping))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Error("expected sendChatAction to return true")
+	}
+}
+
+func TestCallDecodeError(t *testing.T) {
+	bot := newLocalBot(t, func(w http.ResponseWriter, r *http.Request) {
+		writeResult(w, `"not a number"`)
+	})
+
+	if _, err := Call[int](bot, ChatMemberCountConfig{ChatConfig{ChatID: 1}}); err == nil {
+		t.Error("expected an error decoding the result")
+	}
+}
+
+func TestOwnedGiftJSON(t *testing.T) {
+	var gifts OwnedGifts
+	err := json.Unmarshal([]byte(`{"total_count":2,"gifts":[
+		{"type":"regular","gift":{"id":"1","star_count":10},"send_date":1},
+		{"type":"unique","gift":{"base_name":"Cake","name":"Cake-1","number":1},"send_date":2}
+	]}`), &gifts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if gifts.Gifts[0].Gift == nil || gifts.Gifts[0].Gift.StarCount != 10 || gifts.Gifts[0].UniqueGift != nil {
+		t.Errorf("unexpected regular gift %+v", gifts.Gifts[0])
+	}
+	if gifts.Gifts[1].UniqueGift == nil || gifts.Gifts[1].UniqueGift.Name != "Cake-1" || gifts.Gifts[1].Gift != nil {
+		t.Errorf("unexpected unique gift %+v", gifts.Gifts[1])
+	}
+
+	data, err := json.Marshal(gifts.Gifts[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"gift":{`) || !strings.Contains(string(data), `"Cake-1"`) {
+		t.Errorf("unexpected encoding %s", data)
+	}
+}
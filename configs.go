@@ -2,10 +2,13 @@ package tgbotapi
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"net/url"
 	"os"
+	"path"
 	"strconv"
 )
 
@@ -92,6 +95,65 @@ const (
 	// UpdateTypeChatMember is when the bot must be an administrator in the chat and must explicitly specify
 	// this update in the list of allowed_updates to receive these updates.
 	UpdateTypeChatMember = "chat_member"
+
+	// UpdateTypeChatJoinRequest is a request to join the chat. The bot must have the can_invite_users
+	// administrator right in the chat to receive these updates.
+	UpdateTypeChatJoinRequest = "chat_join_request"
+
+	// UpdateTypeChatBoost is when a chat boost was added or changed.
+	UpdateTypeChatBoost = "chat_boost"
+
+	// UpdateTypeRemovedChatBoost is when a boost was removed from a chat.
+	UpdateTypeRemovedChatBoost = "removed_chat_boost"
+
+	// UpdateTypeMessageReaction is when a reaction to a message was changed by a user.
+	UpdateTypeMessageReaction = "message_reaction"
+
+	// UpdateTypeMessageReactionCount is when reactions to a message with anonymous reactions were changed.
+	UpdateTypeMessageReactionCount = "message_reaction_count"
+
+	// UpdateTypeBusinessConnection is when the bot was connected to or disconnected from a business account.
+	UpdateTypeBusinessConnection = "business_connection"
+
+	// UpdateTypeBusinessMessage is a new message from a connected business account.
+	UpdateTypeBusinessMessage = "business_message"
+
+	// UpdateTypeEditedBusinessMessage is new version of a message from a connected business account.
+	UpdateTypeEditedBusinessMessage = "edited_business_message"
+
+	// UpdateTypeDeletedBusinessMessages is when messages were deleted from a connected business account.
+	UpdateTypeDeletedBusinessMessages = "deleted_business_messages"
+
+	// UpdateTypePurchasedPaidMedia is when a user purchased paid media with a non-empty payload sent by the bot.
+	UpdateTypePurchasedPaidMedia = "purchased_paid_media"
+)
+
+// Constant values for message content types, as returned by Message.ContentType
+const (
+	ContentTypeText              = "text"
+	ContentTypeAnimation         = "animation"
+	ContentTypeAudio             = "audio"
+	ContentTypeDocument          = "document"
+	ContentTypePaidMedia         = "paid_media"
+	ContentTypePhoto             = "photo"
+	ContentTypeSticker           = "sticker"
+	ContentTypeStory             = "story"
+	ContentTypeVideo             = "video"
+	ContentTypeVideoNote         = "video_note"
+	ContentTypeVoice             = "voice"
+	ContentTypeChecklist         = "checklist"
+	ContentTypeContact           = "contact"
+	ContentTypeDice              = "dice"
+	ContentTypeGame              = "game"
+	ContentTypePoll              = "poll"
+	ContentTypeVenue             = "venue"
+	ContentTypeLocation          = "location"
+	ContentTypeInvoice           = "invoice"
+	ContentTypeNewChatMembers    = "new_chat_members"
+	ContentTypeLeftChatMember    = "left_chat_member"
+	ContentTypePinnedMessage     = "pinned_message"
+	ContentTypeWebAppData        = "web_app_data"
+	ContentTypeSuccessfulPayment = "successful_payment"
 )
 
 // Library errors
@@ -99,9 +161,10 @@ const (
 	ErrBadURL = "bad or empty url"
 )
 
-// Chattable is any config type that can be sent.
+// Chattable is any config type that can be sent. Requests for methods this
+// package doesn't support can be made with RawRequest.
 type Chattable interface {
-	params() (Params, error)
+	params() (*configParams, error)
 	method() string
 }
 
@@ -111,6 +174,48 @@ type Fileable interface {
 	files() []RequestFile
 }
 
+// RawRequest is a request for any Bot API method, including ones this
+// package doesn't have a config for yet. It is sent like any other config,
+// through Request, Send or Call, and goes through the bot's middleware:
+//
+//	resp, err := bot.Request(tgbotapi.RawRequest{
+//		Method: "sendMessage",
+//		Params: tgbotapi.Params{"chat_id": "123", "text": "Hello"},
+//	})
+//
+// Files are uploaded when at least one of them needs to be, and are otherwise
+// sent as params, like with other configs.
+type RawRequest struct {
+	// Method is the name of the Bot API method, such as sendMessage.
+	Method string
+	// Params are the params of the request. Values that are objects or
+	// arrays must be JSON encoded, for example with Params.AddInterface.
+	Params Params
+	// Files are the files of the request, with Name being the param name.
+	Files []RequestFile
+}
+
+func (req RawRequest) method() string {
+	return req.Method
+}
+
+func (req RawRequest) params() (*configParams, error) {
+	if req.Method == "" {
+		return nil, errors.New("raw request has no method")
+	}
+
+	params := newParams()
+	for key, value := range req.Params {
+		params.Params[key] = value
+	}
+
+	return params, nil
+}
+
+func (req RawRequest) files() []RequestFile {
+	return req.Files
+}
+
 // RequestFile represents a file associated with a field name.
 type RequestFile struct {
 	// The file field name.
@@ -132,6 +237,18 @@ type RequestFileData interface {
 	SendData() string
 }
 
+// ReplayableFileData is RequestFileData that can be uploaded several times,
+// so uploads can be retried. Every call to UploadData must return the whole
+// file.
+type ReplayableFileData interface {
+	RequestFileData
+
+	// UploadSize returns the size of the file to upload, or -1 if it isn't
+	// known before reading it. When the size of every file of a request is
+	// known, the request is sent with a Content-Length.
+	UploadSize() int64
+}
+
 // FileBytes contains information about a set of bytes to upload
 // as a File.
 type FileBytes struct {
@@ -151,6 +268,10 @@ func (fb FileBytes) SendData() string {
 	panic("FileBytes must be uploaded")
 }
 
+func (fb FileBytes) UploadSize() int64 {
+	return int64(len(fb.Bytes))
+}
+
 // FileReader contains information about a reader to upload as a File.
 type FileReader struct {
 	Name   string
@@ -169,6 +290,31 @@ func (fr FileReader) SendData() string {
 	panic("FileReader must be uploaded")
 }
 
+// FileReaderAt contains information about a file to upload from an
+// io.ReaderAt, such as an *os.File or a *bytes.Reader. Unlike FileReader, it
+// can be uploaded again if the request is retried.
+type FileReaderAt struct {
+	Name   string
+	Reader io.ReaderAt
+	Size   int64
+}
+
+func (fr FileReaderAt) NeedsUpload() bool {
+	return true
+}
+
+func (fr FileReaderAt) UploadData() (string, io.Reader, error) {
+	return fr.Name, io.NewSectionReader(fr.Reader, 0, fr.Size), nil
+}
+
+func (fr FileReaderAt) SendData() string {
+	panic("FileReaderAt must be uploaded")
+}
+
+func (fr FileReaderAt) UploadSize() int64 {
+	return fr.Size
+}
+
 // FilePath is a path to a local file.
 type FilePath string
 
@@ -190,6 +336,55 @@ func (fp FilePath) SendData() string {
 	panic("FilePath must be uploaded")
 }
 
+func (fp FilePath) UploadSize() int64 {
+	info, err := os.Stat(string(fp))
+	if err != nil || !info.Mode().IsRegular() {
+		return -1
+	}
+
+	return info.Size()
+}
+
+// FileFS is a file to upload from a file system, such as an embed.FS. The
+// path is a slash-separated path valid for fs.FS. The file is uploaded with
+// Name, or the base name of its path if Name is empty, and is streamed from
+// the file system without being read into memory first.
+type FileFS struct {
+	FS   fs.FS
+	Path string
+	Name string
+}
+
+func (ff FileFS) NeedsUpload() bool {
+	return true
+}
+
+func (ff FileFS) UploadData() (string, io.Reader, error) {
+	file, err := ff.FS.Open(ff.Path)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if ff.Name != "" {
+		return ff.Name, file, nil
+	}
+
+	return path.Base(ff.Path), file, nil
+}
+
+func (ff FileFS) SendData() string {
+	panic("FileFS must be uploaded")
+}
+
+func (ff FileFS) UploadSize() int64 {
+	info, err := fs.Stat(ff.FS, ff.Path)
+	if err != nil || !info.Mode().IsRegular() {
+		return -1
+	}
+
+	return info.Size()
+}
+
 // FileURL is a URL to use as a file for a request.
 type FileURL string
 
@@ -244,8 +439,8 @@ func (LogOutConfig) method() string {
 	return "logOut"
 }
 
-func (LogOutConfig) params() (Params, error) {
-	return nil, nil
+func (LogOutConfig) params() (*configParams, error) {
+	return newParams(), nil
 }
 
 // CloseConfig is a request to close the bot instance on a local server.
@@ -258,8 +453,8 @@ func (CloseConfig) method() string {
 	return "close"
 }
 
-func (CloseConfig) params() (Params, error) {
-	return nil, nil
+func (CloseConfig) params() (*configParams, error) {
+	return newParams(), nil
 }
 
 // BaseChat is base type for all chat config types.
@@ -275,8 +470,8 @@ type BaseChat struct {
 	MessageThreadID          int64
 }
 
-func (chat *BaseChat) params() (Params, error) {
-	params := make(Params)
+func (chat *BaseChat) params() (*configParams, error) {
+	params := newParams()
 
 	params.AddFirstValid("chat_id", chat.ChatID, chat.ChannelUsername)
 	params.AddNonZero("reply_to_message_id", chat.ReplyToMessageID)
@@ -297,7 +492,7 @@ type BaseFile struct {
 	Thumb RequestFileData
 }
 
-func (file BaseFile) params() (Params, error) {
+func (file BaseFile) params() (*configParams, error) {
 	return file.BaseChat.params()
 }
 
@@ -312,11 +507,11 @@ type BaseEdit struct {
 	LinkPreviewOptions   *LinkPreviewOptions
 }
 
-func (edit BaseEdit) params() (Params, error) {
-	params := make(Params)
+func (edit BaseEdit) params() (*configParams, error) {
+	params := newParams()
 
 	if edit.InlineMessageID != "" {
-		params["inline_message_id"] = edit.InlineMessageID
+		params.Params["inline_message_id"] = edit.InlineMessageID
 	} else {
 		params.AddFirstValid("chat_id", edit.ChatID, edit.ChannelUsername)
 		params.AddNonZero("message_id", edit.MessageID)
@@ -342,7 +537,7 @@ type MessageConfig struct {
 	MessageEffectID       string
 }
 
-func (config MessageConfig) params() (Params, error) {
+func (config MessageConfig) params() (*configParams, error) {
 	params, err := config.BaseChat.params()
 	if err != nil {
 		return params, err
@@ -373,7 +568,7 @@ type ForwardConfig struct {
 	VideoStartTimestamp int
 }
 
-func (config ForwardConfig) params() (Params, error) {
+func (config ForwardConfig) params() (*configParams, error) {
 	params, err := config.BaseChat.params()
 	if err != nil {
 		return params, err
@@ -398,7 +593,7 @@ type ForwardMsgsConfig struct {
 	MessageIDs      []int // required
 }
 
-func (config ForwardMsgsConfig) params() (Params, error) {
+func (config ForwardMsgsConfig) params() (*configParams, error) {
 	params, err := config.BaseChat.params()
 	if err != nil {
 		return params, err
@@ -411,7 +606,7 @@ func (config ForwardMsgsConfig) params() (Params, error) {
 }
 
 func (config ForwardMsgsConfig) method() string {
-	return "forwardMessage"
+	return "forwardMessages"
 }
 
 // CopyMessageConfig contains information about a copyMessage request.
@@ -429,7 +624,7 @@ type CopyMessageConfig struct {
 	ReplyParameters       *ReplyParameters
 }
 
-func (config CopyMessageConfig) params() (Params, error) {
+func (config CopyMessageConfig) params() (*configParams, error) {
 	params, err := config.BaseChat.params()
 	if err != nil {
 		return params, err
@@ -461,7 +656,7 @@ type CopyMessagesConfig struct {
 	RemoveCaption       bool
 }
 
-func (config CopyMessagesConfig) params() (Params, error) {
+func (config CopyMessagesConfig) params() (*configParams, error) {
 	params, err := config.BaseChat.params()
 	if err != nil {
 		return params, err
@@ -489,7 +684,7 @@ type PhotoConfig struct {
 	ReplyParameters       *ReplyParameters
 }
 
-func (config PhotoConfig) params() (Params, error) {
+func (config PhotoConfig) params() (*configParams, error) {
 	params, err := config.BaseFile.params()
 	if err != nil {
 		return params, err
@@ -502,7 +697,7 @@ func (config PhotoConfig) params() (Params, error) {
 	params.AddBool("has_spoiler", config.HasSpoiler)
 	params.AddBool("allow_paid_broadcast", config.AllowPaidBroadcast)
 	params.AddInterface("reply_parameters", config.ReplyParameters)
-	err = params.CheckArgs("chat_id", "photo")
+	err = params.CheckArgs("chat_id")
 	if err != nil {
 		return nil, err
 	}
@@ -543,7 +738,7 @@ type AudioConfig struct {
 	ReplyParameters    *ReplyParameters
 }
 
-func (config AudioConfig) params() (Params, error) {
+func (config AudioConfig) params() (*configParams, error) {
 	params, err := config.BaseChat.params()
 	if err != nil {
 		return params, err
@@ -593,7 +788,7 @@ type DocumentConfig struct {
 	ReplyParameters             *ReplyParameters
 }
 
-func (config DocumentConfig) params() (Params, error) {
+func (config DocumentConfig) params() (*configParams, error) {
 	params, err := config.BaseFile.params()
 
 	params.AddNonEmpty("caption", config.Caption)
@@ -635,7 +830,7 @@ type StickerConfig struct {
 	MessageEffectID    string           `json:"message_effect_id,omitempty"`
 }
 
-func (config StickerConfig) params() (Params, error) {
+func (config StickerConfig) params() (*configParams, error) {
 	p, err := config.BaseChat.params()
 	if err != nil {
 		return p, err
@@ -677,7 +872,7 @@ type VideoConfig struct {
 	ReplyParameters       *ReplyParameters
 }
 
-func (config VideoConfig) params() (Params, error) {
+func (config VideoConfig) params() (*configParams, error) {
 	params, err := config.BaseChat.params()
 	if err != nil {
 		return params, err
@@ -740,7 +935,7 @@ type AnimationConfig struct {
 	ReplyParameters       *ReplyParameters
 }
 
-func (config AnimationConfig) params() (Params, error) {
+func (config AnimationConfig) params() (*configParams, error) {
 	params, err := config.BaseChat.params()
 	if err != nil {
 		return params, err
@@ -792,7 +987,7 @@ type VideoNoteConfig struct {
 	MessageEffectID    string
 }
 
-func (config VideoNoteConfig) params() (Params, error) {
+func (config VideoNoteConfig) params() (*configParams, error) {
 	params, err := config.BaseChat.params()
 
 	params.AddNonZero("duration", config.Duration)
@@ -837,7 +1032,7 @@ type PaidMediaConfig struct {
 	ReplyParameters       *ReplyParameters
 }
 
-func (config PaidMediaConfig) params() (Params, error) {
+func (config PaidMediaConfig) params() (*configParams, error) {
 	params, err := config.BaseChat.params()
 
 	params.AddNonZero("star_count", config.StarCount)
@@ -873,7 +1068,7 @@ type VoiceConfig struct {
 	MessageEffectID    string
 }
 
-func (config VoiceConfig) params() (Params, error) {
+func (config VoiceConfig) params() (*configParams, error) {
 	params, err := config.BaseChat.params()
 	if err != nil {
 		return params, err
@@ -917,11 +1112,11 @@ type LocationConfig struct {
 	ReplyParameters    *ReplyParameters
 }
 
-func (config LocationConfig) params() (Params, error) {
+func (config LocationConfig) params() (*configParams, error) {
 	params, err := config.BaseChat.params()
 
-	params.AddNonZeroFloat("latitude", config.Latitude)
-	params.AddNonZeroFloat("longitude", config.Longitude)
+	params.SetFloat("latitude", config.Latitude)
+	params.SetFloat("longitude", config.Longitude)
 	params.AddNonZeroFloat("horizontal_accuracy", config.HorizontalAccuracy)
 	params.AddNonZero("live_period", config.LivePeriod)
 	params.AddNonZero("heading", config.Heading)
@@ -943,7 +1138,7 @@ type DeleteMessagesConfig struct {
 	MessageIDs []int
 }
 
-func (config DeleteMessagesConfig) params() (Params, error) {
+func (config DeleteMessagesConfig) params() (*configParams, error) {
 	params, err := config.BaseChat.params()
 	params.AddInterface("message_ids", config.MessageIDs)
 	return params, err
@@ -956,8 +1151,8 @@ func (config DeleteMessagesConfig) method() string {
 type GetAvailableGiftsConfig struct {
 }
 
-func (config GetAvailableGiftsConfig) params() (Params, error) {
-	return Params{}, nil
+func (config GetAvailableGiftsConfig) params() (*configParams, error) {
+	return newParams(), nil
 }
 
 func (config GetAvailableGiftsConfig) method() string {
@@ -975,8 +1170,8 @@ type SendGiftConfig struct {
 	TextEntities  []MessageEntity
 }
 
-func (config SendGiftConfig) params() (Params, error) {
-	params := Params{}
+func (config SendGiftConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonZero64("user_id", config.UserID)
 	params.AddFirstValid("chat_id", config.ChatID, config.UserName)
 	params.AddNonEmpty("gift_id", config.GiftID)
@@ -1013,8 +1208,8 @@ type GiftPremiumSubscriptionConfig struct {
 	TextEntities  []MessageEntity
 }
 
-func (config GiftPremiumSubscriptionConfig) params() (Params, error) {
-	params := Params{}
+func (config GiftPremiumSubscriptionConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonZero64("user_id", config.UserID)
 	params.AddNonZero("month_count", config.MonthCount)
 	params.AddNonZero("star_count", config.StarCount)
@@ -1041,8 +1236,8 @@ type VerifyUserConfig struct {
 	CustomDescription string
 }
 
-func (config VerifyUserConfig) params() (Params, error) {
-	params := Params{}
+func (config VerifyUserConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonZero64("user_id", config.UserID)
 	params.AddNonEmpty("custom_description", config.CustomDescription)
 	return params, nil
@@ -1065,8 +1260,8 @@ type VerifyChatConfig struct {
 	CustomDescription string
 }
 
-func (config VerifyChatConfig) params() (Params, error) {
-	params := Params{}
+func (config VerifyChatConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonZero64("chat_id", config.ChatID)
 	params.AddNonEmpty("custom_description", config.CustomDescription)
 	return params, nil
@@ -1087,8 +1282,8 @@ type RemoveUserVerificationConfig struct {
 	UserID int64
 }
 
-func (config RemoveUserVerificationConfig) params() (Params, error) {
-	params := Params{}
+func (config RemoveUserVerificationConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonZero64("user_id", config.UserID)
 	return params, nil
 }
@@ -1108,11 +1303,11 @@ type EditMessageLiveLocationConfig struct {
 	LivePeriod           int     // optional
 }
 
-func (config EditMessageLiveLocationConfig) params() (Params, error) {
+func (config EditMessageLiveLocationConfig) params() (*configParams, error) {
 	params, err := config.BaseEdit.params()
 
-	params.AddNonZeroFloat("latitude", config.Latitude)
-	params.AddNonZeroFloat("longitude", config.Longitude)
+	params.SetFloat("latitude", config.Latitude)
+	params.SetFloat("longitude", config.Longitude)
 	params.AddNonZeroFloat("horizontal_accuracy", config.HorizontalAccuracy)
 	params.AddNonZero("heading", config.Heading)
 	params.AddNonZero("proximity_alert_radius", config.ProximityAlertRadius)
@@ -1130,7 +1325,7 @@ type StopMessageLiveLocationConfig struct {
 	BaseEdit
 }
 
-func (config StopMessageLiveLocationConfig) params() (Params, error) {
+func (config StopMessageLiveLocationConfig) params() (*configParams, error) {
 	return config.BaseEdit.params()
 }
 
@@ -1154,13 +1349,13 @@ type VenueConfig struct {
 	ReplyParameters    *ReplyParameters
 }
 
-func (config VenueConfig) params() (Params, error) {
+func (config VenueConfig) params() (*configParams, error) {
 	params, err := config.BaseChat.params()
 
-	params.AddNonZeroFloat("latitude", config.Latitude)
-	params.AddNonZeroFloat("longitude", config.Longitude)
-	params["title"] = config.Title
-	params["address"] = config.Address
+	params.SetFloat("latitude", config.Latitude)
+	params.SetFloat("longitude", config.Longitude)
+	params.Params["title"] = config.Title
+	params.Params["address"] = config.Address
 	params.AddNonEmpty("foursquare_id", config.FoursquareID)
 	params.AddNonEmpty("foursquare_type", config.FoursquareType)
 	params.AddNonEmpty("google_place_id", config.GooglePlaceID)
@@ -1188,11 +1383,11 @@ type ContactConfig struct {
 	ReplyParameters    *ReplyParameters
 }
 
-func (config ContactConfig) params() (Params, error) {
+func (config ContactConfig) params() (*configParams, error) {
 	params, err := config.BaseChat.params()
 
-	params["phone_number"] = config.PhoneNumber
-	params["first_name"] = config.FirstName
+	params.Params["phone_number"] = config.PhoneNumber
+	params.Params["first_name"] = config.FirstName
 
 	params.AddNonEmpty("last_name", config.LastName)
 	params.AddNonEmpty("vcard", config.VCard)
@@ -1214,7 +1409,7 @@ type MessageReactionConfig struct {
 	IsBig     bool
 }
 
-func (config MessageReactionConfig) params() (Params, error) {
+func (config MessageReactionConfig) params() (*configParams, error) {
 	params, err := config.BaseChat.params()
 
 	params.AddNonZero("message_id", config.MessageID)
@@ -1234,8 +1429,8 @@ type UserEmojiStatusConfig struct {
 	EmojiStatusExpirationDate int
 }
 
-func (config UserEmojiStatusConfig) params() (Params, error) {
-	params := make(Params)
+func (config UserEmojiStatusConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonZero64("user_id", config.UserID)
 	params.AddNonEmpty("emoji_status_custom_emoji_id", config.EmojiStatusCustomEmojiID)
 	params.AddNonZero("emoji_status_expiration_date", config.EmojiStatusExpirationDate)
@@ -1268,21 +1463,21 @@ type SendPollConfig struct {
 	AllowPaidBroadcast    bool
 }
 
-func (config SendPollConfig) params() (Params, error) {
+func (config SendPollConfig) params() (*configParams, error) {
 	params, err := config.BaseChat.params()
 	if err != nil {
 		return params, err
 	}
 
-	params["question"] = config.Question
+	params.Params["question"] = config.Question
 	params.AddNonEmpty("question_parse_mode", config.QuestionParseMode)
 	if err = params.AddInterface("question_entities", config.QuestionEntities); err != nil {
 		return params, err
 	}
-	params["is_anonymous"] = strconv.FormatBool(config.IsAnonymous)
+	params.Params["is_anonymous"] = strconv.FormatBool(config.IsAnonymous)
 	params.AddNonEmpty("type", config.Type)
-	params["allows_multiple_answers"] = strconv.FormatBool(config.AllowsMultipleAnswers)
-	params["correct_option_id"] = strconv.FormatInt(config.CorrectOptionID, 10)
+	params.Params["allows_multiple_answers"] = strconv.FormatBool(config.AllowsMultipleAnswers)
+	params.Params["correct_option_id"] = strconv.FormatInt(config.CorrectOptionID, 10)
 	params.AddBool("is_closed", config.IsClosed)
 	params.AddNonEmpty("explanation", config.Explanation)
 	params.AddNonEmpty("explanation_parse_mode", config.ExplanationParseMode)
@@ -1306,7 +1501,7 @@ type ChatActionConfig struct {
 	Action string `json:"action"`
 }
 
-func (config ChatActionConfig) params() (Params, error) {
+func (config ChatActionConfig) params() (*configParams, error) {
 	params, err := config.BaseChat.params()
 	if err != nil {
 		return nil, err
@@ -1332,13 +1527,13 @@ type EditMessageTextConfig struct {
 	DisableWebPagePreview bool
 }
 
-func (config EditMessageTextConfig) params() (Params, error) {
+func (config EditMessageTextConfig) params() (*configParams, error) {
 	params, err := config.BaseEdit.params()
 	if err != nil {
 		return params, err
 	}
 
-	params["text"] = config.Text
+	params.Params["text"] = config.Text
 	params.AddNonEmpty("parse_mode", config.ParseMode)
 	params.AddBool("disable_web_page_preview", config.DisableWebPagePreview)
 	err = params.AddInterface("entities", config.Entities)
@@ -1359,13 +1554,13 @@ type EditMessageCaptionConfig struct {
 	ShowCaptionAboveMedia bool
 }
 
-func (config EditMessageCaptionConfig) params() (Params, error) {
+func (config EditMessageCaptionConfig) params() (*configParams, error) {
 	params, err := config.BaseEdit.params()
 	if err != nil {
 		return params, err
 	}
 
-	params["caption"] = config.Caption
+	params.Params["caption"] = config.Caption
 	params.AddNonEmpty("parse_mode", config.ParseMode)
 	params.AddBool("show_caption_above_media", config.ShowCaptionAboveMedia)
 	err = params.AddInterface("caption_entities", config.CaptionEntities)
@@ -1388,7 +1583,7 @@ func (EditMessageMediaConfig) method() string {
 	return "editMessageMedia"
 }
 
-func (config EditMessageMediaConfig) params() (Params, error) {
+func (config EditMessageMediaConfig) params() (*configParams, error) {
 	params, err := config.BaseEdit.params()
 	if err != nil {
 		return params, err
@@ -1409,7 +1604,7 @@ type EditMessageReplyMarkupConfig struct {
 	BaseEdit
 }
 
-func (config EditMessageReplyMarkupConfig) params() (Params, error) {
+func (config EditMessageReplyMarkupConfig) params() (*configParams, error) {
 	return config.BaseEdit.params()
 }
 
@@ -1422,7 +1617,7 @@ type StopPollConfig struct {
 	BaseEdit
 }
 
-func (config StopPollConfig) params() (Params, error) {
+func (config StopPollConfig) params() (*configParams, error) {
 	return config.BaseEdit.params()
 }
 
@@ -1442,8 +1637,8 @@ func (UserProfilePhotosConfig) method() string {
 	return "getUserProfilePhotos"
 }
 
-func (config UserProfilePhotosConfig) params() (Params, error) {
-	params := make(Params)
+func (config UserProfilePhotosConfig) params() (*configParams, error) {
+	params := newParams()
 
 	params.AddNonZero64("user_id", config.UserID)
 	params.AddNonZero("offset", config.Offset)
@@ -1461,10 +1656,10 @@ func (FileConfig) method() string {
 	return "getFile"
 }
 
-func (config FileConfig) params() (Params, error) {
-	params := make(Params)
+func (config FileConfig) params() (*configParams, error) {
+	params := newParams()
 
-	params["file_id"] = config.FileID
+	params.Params["file_id"] = config.FileID
 
 	return params, nil
 }
@@ -1481,8 +1676,8 @@ func (UpdateConfig) method() string {
 	return "getUpdates"
 }
 
-func (config UpdateConfig) params() (Params, error) {
-	params := make(Params)
+func (config UpdateConfig) params() (*configParams, error) {
+	params := newParams()
 
 	params.AddNonZero("offset", config.Offset)
 	params.AddNonZero("limit", config.Limit)
@@ -1511,11 +1706,15 @@ func (config WebhookInfo) method() string {
 	return "getWebhookInfo"
 }
 
-func (config WebhookConfig) params() (Params, error) {
-	params := make(Params)
+func (config WebhookInfo) params() (*configParams, error) {
+	return newParams(), nil
+}
+
+func (config WebhookConfig) params() (*configParams, error) {
+	params := newParams()
 
 	if config.URL != nil {
-		params["url"] = config.URL.String()
+		params.Params["url"] = config.URL.String()
 	}
 
 	params.AddNonEmpty("ip_address", config.IPAddress)
@@ -1547,8 +1746,8 @@ func (config DeleteWebhookConfig) method() string {
 	return "deleteWebhook"
 }
 
-func (config DeleteWebhookConfig) params() (Params, error) {
-	params := make(Params)
+func (config DeleteWebhookConfig) params() (*configParams, error) {
+	params := newParams()
 
 	params.AddBool("drop_pending_updates", config.DropPendingUpdates)
 
@@ -1585,10 +1784,10 @@ func (config InlineConfig) method() string {
 	return "answerInlineQuery"
 }
 
-func (config InlineConfig) params() (Params, error) {
-	params := make(Params)
+func (config InlineConfig) params() (*configParams, error) {
+	params := newParams()
 
-	params["inline_query_id"] = config.InlineQueryID
+	params.Params["inline_query_id"] = config.InlineQueryID
 	params.AddNonZero("cache_time", config.CacheTime)
 	params.AddBool("is_personal", config.IsPersonal)
 	params.AddNonEmpty("next_offset", config.NextOffset)
@@ -1614,10 +1813,10 @@ func (config AnswerWebAppQueryConfig) method() string {
 	return "answerWebAppQuery"
 }
 
-func (config AnswerWebAppQueryConfig) params() (Params, error) {
-	params := make(Params)
+func (config AnswerWebAppQueryConfig) params() (*configParams, error) {
+	params := newParams()
 
-	params["web_app_query_id"] = config.WebAppQueryID
+	params.Params["web_app_query_id"] = config.WebAppQueryID
 	err := params.AddInterface("result", config.Result)
 
 	return params, err
@@ -1650,10 +1849,10 @@ func (config CallbackConfig) method() string {
 	return "answerCallbackQuery"
 }
 
-func (config CallbackConfig) params() (Params, error) {
-	params := make(Params)
+func (config CallbackConfig) params() (*configParams, error) {
+	params := newParams()
 
-	params["callback_query_id"] = config.CallbackQueryID
+	params.Params["callback_query_id"] = config.CallbackQueryID
 	params.AddNonEmpty("text", config.Text)
 	params.AddBool("show_alert", config.ShowAlert)
 	params.AddNonEmpty("url", config.URL)
@@ -1681,8 +1880,8 @@ func (config UnbanChatMemberConfig) method() string {
 	return "unbanChatMember"
 }
 
-func (config UnbanChatMemberConfig) params() (Params, error) {
-	params := make(Params)
+func (config UnbanChatMemberConfig) params() (*configParams, error) {
+	params := newParams()
 
 	params.AddFirstValid("chat_id", config.ChatID, config.SuperGroupUsername, config.ChannelUsername)
 	params.AddNonZero64("user_id", config.UserID)
@@ -1702,8 +1901,8 @@ func (config BanChatMemberConfig) method() string {
 	return "banChatMember"
 }
 
-func (config BanChatMemberConfig) params() (Params, error) {
-	params := make(Params)
+func (config BanChatMemberConfig) params() (*configParams, error) {
+	params := newParams()
 
 	params.AddFirstValid("chat_id", config.ChatID, config.SuperGroupUsername)
 	params.AddNonZero64("user_id", config.UserID)
@@ -1730,8 +1929,8 @@ func (config RestrictChatMemberConfig) method() string {
 	return "restrictChatMember"
 }
 
-func (config RestrictChatMemberConfig) params() (Params, error) {
-	params := make(Params)
+func (config RestrictChatMemberConfig) params() (*configParams, error) {
+	params := newParams()
 
 	params.AddFirstValid("chat_id", config.ChatID, config.SuperGroupUsername, config.ChannelUsername)
 	params.AddNonZero64("user_id", config.UserID)
@@ -1767,8 +1966,8 @@ func (config PromoteChatMemberConfig) method() string {
 	return "promoteChatMember"
 }
 
-func (config PromoteChatMemberConfig) params() (Params, error) {
-	params := make(Params)
+func (config PromoteChatMemberConfig) params() (*configParams, error) {
+	params := newParams()
 
 	params.AddFirstValid("chat_id", config.ChatID, config.SuperGroupUsername, config.ChannelUsername)
 	params.AddNonZero64("user_id", config.UserID)
@@ -1803,8 +2002,8 @@ func (SetChatAdministratorCustomTitle) method() string {
 	return "setChatAdministratorCustomTitle"
 }
 
-func (config SetChatAdministratorCustomTitle) params() (Params, error) {
-	params := make(Params)
+func (config SetChatAdministratorCustomTitle) params() (*configParams, error) {
+	params := newParams()
 
 	params.AddFirstValid("chat_id", config.ChatID, config.SuperGroupUsername, config.ChannelUsername)
 	params.AddNonZero64("user_id", config.UserID)
@@ -1829,8 +2028,8 @@ func (config BanChatSenderChatConfig) method() string {
 	return "banChatSenderChat"
 }
 
-func (config BanChatSenderChatConfig) params() (Params, error) {
-	params := make(Params)
+func (config BanChatSenderChatConfig) params() (*configParams, error) {
+	params := newParams()
 
 	_ = params.AddFirstValid("chat_id", config.ChatID, config.ChannelUsername)
 	params.AddNonZero64("sender_chat_id", config.SenderChatID)
@@ -1852,8 +2051,8 @@ func (config UnbanChatSenderChatConfig) method() string {
 	return "unbanChatSenderChat"
 }
 
-func (config UnbanChatSenderChatConfig) params() (Params, error) {
-	params := make(Params)
+func (config UnbanChatSenderChatConfig) params() (*configParams, error) {
+	params := newParams()
 
 	_ = params.AddFirstValid("chat_id", config.ChatID, config.ChannelUsername)
 	params.AddNonZero64("sender_chat_id", config.SenderChatID)
@@ -1867,8 +2066,8 @@ type ChatConfig struct {
 	SuperGroupUsername string
 }
 
-func (config ChatConfig) params() (Params, error) {
-	params := make(Params)
+func (config ChatConfig) params() (*configParams, error) {
+	params := newParams()
 
 	params.AddFirstValid("chat_id", config.ChatID, config.SuperGroupUsername)
 
@@ -1915,8 +2114,8 @@ func (SetChatPermissionsConfig) method() string {
 	return "setChatPermissions"
 }
 
-func (config SetChatPermissionsConfig) params() (Params, error) {
-	params := make(Params)
+func (config SetChatPermissionsConfig) params() (*configParams, error) {
+	params := newParams()
 
 	params.AddFirstValid("chat_id", config.ChatID, config.SuperGroupUsername)
 	err := params.AddInterface("permissions", config.Permissions)
@@ -1946,8 +2145,8 @@ func (EditChatSubscriptionInviteLinkConfig) method() string {
 	return "editChatSubscriptionInviteLink"
 }
 
-func (config EditChatSubscriptionInviteLinkConfig) params() (Params, error) {
-	params := make(Params)
+func (config EditChatSubscriptionInviteLinkConfig) params() (*configParams, error) {
+	params := newParams()
 
 	params.AddFirstValid("chat_id", config.ChatID, config.Name)
 	params.AddNonEmpty("invite_link", config.InviteLink)
@@ -1966,8 +2165,8 @@ func (ChatInviteLinkConfig) method() string {
 	return "exportChatInviteLink"
 }
 
-func (config ChatInviteLinkConfig) params() (Params, error) {
-	params := make(Params)
+func (config ChatInviteLinkConfig) params() (*configParams, error) {
+	params := newParams()
 
 	params.AddFirstValid("chat_id", config.ChatID, config.SuperGroupUsername)
 
@@ -1990,8 +2189,8 @@ func (CreateChatInviteLinkConfig) method() string {
 	return "createChatInviteLink"
 }
 
-func (config CreateChatInviteLinkConfig) params() (Params, error) {
-	params := make(Params)
+func (config CreateChatInviteLinkConfig) params() (*configParams, error) {
+	params := newParams()
 
 	params.AddNonEmpty("name", config.Name)
 	params.AddFirstValid("chat_id", config.ChatID, config.SuperGroupUsername)
@@ -2018,12 +2217,12 @@ func (EditChatInviteLinkConfig) method() string {
 	return "editChatInviteLink"
 }
 
-func (config EditChatInviteLinkConfig) params() (Params, error) {
-	params := make(Params)
+func (config EditChatInviteLinkConfig) params() (*configParams, error) {
+	params := newParams()
 
 	params.AddFirstValid("chat_id", config.ChatID, config.SuperGroupUsername)
 	params.AddNonEmpty("name", config.Name)
-	params["invite_link"] = config.InviteLink
+	params.Params["invite_link"] = config.InviteLink
 	params.AddNonZero("expire_date", config.ExpireDate)
 	params.AddNonZero("member_limit", config.MemberLimit)
 	params.AddBool("creates_join_request", config.CreatesJoinRequest)
@@ -2044,11 +2243,11 @@ func (RevokeChatInviteLinkConfig) method() string {
 	return "revokeChatInviteLink"
 }
 
-func (config RevokeChatInviteLinkConfig) params() (Params, error) {
-	params := make(Params)
+func (config RevokeChatInviteLinkConfig) params() (*configParams, error) {
+	params := newParams()
 
 	params.AddFirstValid("chat_id", config.ChatID, config.SuperGroupUsername)
-	params["invite_link"] = config.InviteLink
+	params.Params["invite_link"] = config.InviteLink
 
 	return params, nil
 }
@@ -2063,8 +2262,8 @@ func (ApproveChatJoinRequestConfig) method() string {
 	return "approveChatJoinRequest"
 }
 
-func (config ApproveChatJoinRequestConfig) params() (Params, error) {
-	params := make(Params)
+func (config ApproveChatJoinRequestConfig) params() (*configParams, error) {
+	params := newParams()
 
 	params.AddFirstValid("chat_id", config.ChatID, config.SuperGroupUsername)
 	params.AddNonZero("user_id", int(config.UserID))
@@ -2082,8 +2281,8 @@ func (DeclineChatJoinRequest) method() string {
 	return "declineChatJoinRequest"
 }
 
-func (config DeclineChatJoinRequest) params() (Params, error) {
-	params := make(Params)
+func (config DeclineChatJoinRequest) params() (*configParams, error) {
+	params := newParams()
 
 	params.AddFirstValid("chat_id", config.ChatID, config.SuperGroupUsername)
 	params.AddNonZero("user_id", int(config.UserID))
@@ -2101,8 +2300,8 @@ func (config LeaveChatConfig) method() string {
 	return "leaveChat"
 }
 
-func (config LeaveChatConfig) params() (Params, error) {
-	params := make(Params)
+func (config LeaveChatConfig) params() (*configParams, error) {
+	params := newParams()
 
 	params.AddFirstValid("chat_id", config.ChatID, config.ChannelUsername)
 
@@ -2116,8 +2315,8 @@ type ChatConfigWithUser struct {
 	UserID             int64
 }
 
-func (config ChatConfigWithUser) params() (Params, error) {
-	params := make(Params)
+func (config ChatConfigWithUser) params() (*configParams, error) {
+	params := newParams()
 
 	params.AddFirstValid("chat_id", config.ChatID, config.SuperGroupUsername)
 	params.AddNonZero64("user_id", config.UserID)
@@ -2146,10 +2345,10 @@ func (config ShippingConfig) method() string {
 	return "answerShippingQuery"
 }
 
-func (config ShippingConfig) params() (Params, error) {
-	params := make(Params)
+func (config ShippingConfig) params() (*configParams, error) {
+	params := newParams()
 
-	params["shipping_query_id"] = config.ShippingQueryID
+	params.Params["shipping_query_id"] = config.ShippingQueryID
 	params.AddBool("ok", config.OK)
 	err := params.AddInterface("shipping_options", config.ShippingOptions)
 	params.AddNonEmpty("error_message", config.ErrorMessage)
@@ -2168,10 +2367,10 @@ func (config PreCheckoutConfig) method() string {
 	return "answerPreCheckoutQuery"
 }
 
-func (config PreCheckoutConfig) params() (Params, error) {
-	params := make(Params)
+func (config PreCheckoutConfig) params() (*configParams, error) {
+	params := newParams()
 
-	params["pre_checkout_query_id"] = config.PreCheckoutQueryID
+	params.Params["pre_checkout_query_id"] = config.PreCheckoutQueryID
 	params.AddBool("ok", config.OK)
 	params.AddNonEmpty("error_message", config.ErrorMessage)
 
@@ -2189,8 +2388,8 @@ func (config DeleteMessageConfig) method() string {
 	return "deleteMessage"
 }
 
-func (config DeleteMessageConfig) params() (Params, error) {
-	params := make(Params)
+func (config DeleteMessageConfig) params() (*configParams, error) {
+	params := newParams()
 
 	params.AddFirstValid("chat_id", config.ChatID, config.ChannelUsername)
 	params.AddNonZero("message_id", config.MessageID)
@@ -2211,8 +2410,8 @@ func (config PinChatMessageConfig) method() string {
 	return "pinChatMessage"
 }
 
-func (config PinChatMessageConfig) params() (Params, error) {
-	params := make(Params)
+func (config PinChatMessageConfig) params() (*configParams, error) {
+	params := newParams()
 
 	params.AddFirstValid("chat_id", config.ChatID, config.ChannelUsername)
 	params.AddNonZero("message_id", config.MessageID)
@@ -2236,8 +2435,8 @@ func (config UnpinChatMessageConfig) method() string {
 	return "unpinChatMessage"
 }
 
-func (config UnpinChatMessageConfig) params() (Params, error) {
-	params := make(Params)
+func (config UnpinChatMessageConfig) params() (*configParams, error) {
+	params := newParams()
 
 	params.AddFirstValid("chat_id", config.ChatID, config.ChannelUsername)
 	params.AddNonZero("message_id", config.MessageID)
@@ -2253,8 +2452,8 @@ func (config ForumTopicIconStickersConfig) method() string {
 	return "getForumTopicIconStickers"
 }
 
-func (config ForumTopicIconStickersConfig) params() (Params, error) {
-	params := make(Params)
+func (config ForumTopicIconStickersConfig) params() (*configParams, error) {
+	params := newParams()
 	return params, nil
 }
 
@@ -2275,8 +2474,8 @@ func (config ForumTopicConfig) method() string {
 	return "createForumTopic"
 }
 
-func (config ForumTopicConfig) params() (Params, error) {
-	params := make(Params)
+func (config ForumTopicConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddFirstValid("chat_id", config.ChatID, config.ChannelUsername)
 	params.AddNonEmpty("name", config.Name)
 	params.AddNonZero("icon_color", config.IconColor)
@@ -2292,8 +2491,8 @@ func (config EditForumTopicConfig) method() string {
 	return "editForumTopic"
 }
 
-func (config EditForumTopicConfig) params() (Params, error) {
-	params := make(Params)
+func (config EditForumTopicConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonZero("message_thread_id", config.MessageThreadID)
 	params.AddFirstValid("chat_id", config.ChatID, config.ChannelUsername)
 	params.AddNonEmpty("name", config.Name)
@@ -2309,8 +2508,8 @@ func (config CloseForumTopicConfig) method() string {
 	return "closeForumTopic"
 }
 
-func (config CloseForumTopicConfig) params() (Params, error) {
-	params := make(Params)
+func (config CloseForumTopicConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddFirstValid("chat_id", config.ChatID, config.ChannelUsername)
 	params.AddNonZero("message_thread_id", config.MessageThreadID)
 	return params, nil
@@ -2324,8 +2523,8 @@ func (config ReopenForumTopicConfig) method() string {
 	return "reopenForumTopic"
 }
 
-func (config ReopenForumTopicConfig) params() (Params, error) {
-	params := make(Params)
+func (config ReopenForumTopicConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddFirstValid("chat_id", config.ChatID, config.ChannelUsername)
 	params.AddNonZero("message_thread_id", config.MessageThreadID)
 	return params, nil
@@ -2339,8 +2538,8 @@ func (config DeleteForumTopicConfig) method() string {
 	return "deleteForumTopic"
 }
 
-func (config DeleteForumTopicConfig) params() (Params, error) {
-	params := make(Params)
+func (config DeleteForumTopicConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddFirstValid("chat_id", config.ChatID, config.ChannelUsername)
 	params.AddNonZero("message_thread_id", config.MessageThreadID)
 	return params, nil
@@ -2354,8 +2553,8 @@ func (config UnpinAllForumTopicMessagesConfig) method() string {
 	return "unpinAllForumTopicMessages"
 }
 
-func (config UnpinAllForumTopicMessagesConfig) params() (Params, error) {
-	params := make(Params)
+func (config UnpinAllForumTopicMessagesConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddFirstValid("chat_id", config.ChatID, config.ChannelUsername)
 	params.AddNonZero("message_thread_id", config.MessageThreadID)
 	return params, nil
@@ -2369,8 +2568,8 @@ func (config EditGeneralForumTopicConfig) method() string {
 	return "editGeneralForumTopic"
 }
 
-func (config EditGeneralForumTopicConfig) params() (Params, error) {
-	params := make(Params)
+func (config EditGeneralForumTopicConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddFirstValid("chat_id", config.ChatID, config.ChannelUsername)
 	params.AddNonZero("message_thread_id", config.MessageThreadID)
 	return params, nil
@@ -2384,8 +2583,8 @@ func (config CloseGeneralForumTopicConfig) method() string {
 	return "closeGeneralForumTopic"
 }
 
-func (config CloseGeneralForumTopicConfig) params() (Params, error) {
-	params := make(Params)
+func (config CloseGeneralForumTopicConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddFirstValid("chat_id", config.ChatID, config.ChannelUsername)
 	return params, nil
 }
@@ -2398,8 +2597,8 @@ func (config ReopenGeneralForumTopicConfig) method() string {
 	return "reopenGeneralForumTopic"
 }
 
-func (config ReopenGeneralForumTopicConfig) params() (Params, error) {
-	params := make(Params)
+func (config ReopenGeneralForumTopicConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddFirstValid("chat_id", config.ChatID, config.ChannelUsername)
 	return params, nil
 }
@@ -2412,8 +2611,8 @@ func (config HideGeneralForumTopicConfig) method() string {
 	return "hideGeneralForumTopic"
 }
 
-func (config HideGeneralForumTopicConfig) params() (Params, error) {
-	params := make(Params)
+func (config HideGeneralForumTopicConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddFirstValid("chat_id", config.ChatID, config.ChannelUsername)
 	return params, nil
 }
@@ -2426,8 +2625,8 @@ func (config UnhideGeneralForumTopicConfig) method() string {
 	return "unhideGeneralForumTopic"
 }
 
-func (config UnhideGeneralForumTopicConfig) params() (Params, error) {
-	params := make(Params)
+func (config UnhideGeneralForumTopicConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddFirstValid("chat_id", config.ChatID, config.ChannelUsername)
 	return params, nil
 }
@@ -2440,8 +2639,8 @@ func (config UnpinAllGeneralForumTopicMessagesConfig) method() string {
 	return "unpinAllGeneralForumTopicMessages"
 }
 
-func (config UnpinAllGeneralForumTopicMessagesConfig) params() (Params, error) {
-	params := make(Params)
+func (config UnpinAllGeneralForumTopicMessagesConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddFirstValid("chat_id", config.ChatID, config.ChannelUsername)
 	return params, nil
 }
@@ -2456,8 +2655,8 @@ func (config UserChatBoostsConfig) method() string {
 	return "getUserChatBoosts"
 }
 
-func (config UserChatBoostsConfig) params() (Params, error) {
-	params := make(Params)
+func (config UserChatBoostsConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddFirstValid("chat_id", config.ChatID, config.ChannelUsername)
 	params.AddNonZero64("user_id", config.UserID)
 	return params, nil
@@ -2471,8 +2670,8 @@ func (config BusinessConnectionConfig) method() string {
 	return "getBusinessConnection"
 }
 
-func (config BusinessConnectionConfig) params() (Params, error) {
-	params := make(Params)
+func (config BusinessConnectionConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonEmpty("business_connection_id", config.BusinessConnectionID)
 	return params, nil
 }
@@ -2486,8 +2685,8 @@ func (config SetMyNameConfig) method() string {
 	return "setMyName"
 }
 
-func (config SetMyNameConfig) params() (Params, error) {
-	params := make(Params)
+func (config SetMyNameConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonEmpty("name", config.Name)
 	params.AddNonEmpty("language_code", config.LanguageCode)
 	return params, nil
@@ -2501,8 +2700,8 @@ func (config GetMyNameConfig) method() string {
 	return "getMyName"
 }
 
-func (config GetMyNameConfig) params() (Params, error) {
-	params := make(Params)
+func (config GetMyNameConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonEmpty("language_code", config.LanguageCode)
 	return params, nil
 }
@@ -2516,8 +2715,8 @@ func (config SetMyDescriptionConfig) method() string {
 	return "setMyDescription"
 }
 
-func (config SetMyDescriptionConfig) params() (Params, error) {
-	params := make(Params)
+func (config SetMyDescriptionConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonEmpty("description", config.Description)
 	params.AddNonEmpty("language_code", config.LanguageCode)
 	return params, nil
@@ -2531,8 +2730,8 @@ func (config GetMyDescriptionConfig) method() string {
 	return "getMyDescription"
 }
 
-func (config GetMyDescriptionConfig) params() (Params, error) {
-	params := make(Params)
+func (config GetMyDescriptionConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonEmpty("language_code", config.LanguageCode)
 	return params, nil
 }
@@ -2546,8 +2745,8 @@ func (config SetMyShortDescriptionConfig) method() string {
 	return "setMyShortDescription"
 }
 
-func (config SetMyShortDescriptionConfig) params() (Params, error) {
-	params := make(Params)
+func (config SetMyShortDescriptionConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonEmpty("short_description", config.ShortDescription)
 	params.AddNonEmpty("language_code", config.LanguageCode)
 	return params, nil
@@ -2561,8 +2760,8 @@ func (config GetMyShortDescriptionConfig) method() string {
 	return "getMyShortDescription"
 }
 
-func (config GetMyShortDescriptionConfig) params() (Params, error) {
-	params := make(Params)
+func (config GetMyShortDescriptionConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonEmpty("language_code", config.LanguageCode)
 	return params, nil
 }
@@ -2578,8 +2777,8 @@ func (config UnpinAllChatMessagesConfig) method() string {
 	return "unpinAllChatMessages"
 }
 
-func (config UnpinAllChatMessagesConfig) params() (Params, error) {
-	params := make(Params)
+func (config UnpinAllChatMessagesConfig) params() (*configParams, error) {
+	params := newParams()
 
 	params.AddFirstValid("chat_id", config.ChatID, config.ChannelUsername)
 
@@ -2612,8 +2811,8 @@ func (config DeleteChatPhotoConfig) method() string {
 	return "deleteChatPhoto"
 }
 
-func (config DeleteChatPhotoConfig) params() (Params, error) {
-	params := make(Params)
+func (config DeleteChatPhotoConfig) params() (*configParams, error) {
+	params := newParams()
 
 	params.AddFirstValid("chat_id", config.ChatID, config.ChannelUsername)
 
@@ -2632,11 +2831,11 @@ func (config SetChatTitleConfig) method() string {
 	return "setChatTitle"
 }
 
-func (config SetChatTitleConfig) params() (Params, error) {
-	params := make(Params)
+func (config SetChatTitleConfig) params() (*configParams, error) {
+	params := newParams()
 
 	params.AddFirstValid("chat_id", config.ChatID, config.ChannelUsername)
-	params["title"] = config.Title
+	params.Params["title"] = config.Title
 
 	return params, nil
 }
@@ -2653,11 +2852,11 @@ func (config SetChatDescriptionConfig) method() string {
 	return "setChatDescription"
 }
 
-func (config SetChatDescriptionConfig) params() (Params, error) {
-	params := make(Params)
+func (config SetChatDescriptionConfig) params() (*configParams, error) {
+	params := newParams()
 
 	params.AddFirstValid("chat_id", config.ChatID, config.ChannelUsername)
-	params["description"] = config.Description
+	params.Params["description"] = config.Description
 
 	return params, nil
 }
@@ -2684,8 +2883,8 @@ func (config MediaGroupConfig) method() string {
 	return "sendMediaGroup"
 }
 
-func (config MediaGroupConfig) params() (Params, error) {
-	params := make(Params)
+func (config MediaGroupConfig) params() (*configParams, error) {
+	params := newParams()
 
 	params.AddFirstValid("chat_id", config.ChatID, config.ChannelUsername)
 	params.AddBool("disable_notification", config.DisableNotification)
@@ -2724,7 +2923,7 @@ func (config DiceConfig) method() string {
 	return "sendDice"
 }
 
-func (config DiceConfig) params() (Params, error) {
+func (config DiceConfig) params() (*configParams, error) {
 	params, err := config.BaseChat.params()
 	if err != nil {
 		return params, err
@@ -2748,8 +2947,8 @@ func (config GetMyCommandsConfig) method() string {
 	return "getMyCommands"
 }
 
-func (config GetMyCommandsConfig) params() (Params, error) {
-	params := make(Params)
+func (config GetMyCommandsConfig) params() (*configParams, error) {
+	params := newParams()
 
 	err := params.AddInterface("scope", config.Scope)
 	params.AddNonEmpty("language_code", config.LanguageCode)
@@ -2768,8 +2967,8 @@ func (config SetMyCommandsConfig) method() string {
 	return "setMyCommands"
 }
 
-func (config SetMyCommandsConfig) params() (Params, error) {
-	params := make(Params)
+func (config SetMyCommandsConfig) params() (*configParams, error) {
+	params := newParams()
 
 	if err := params.AddInterface("commands", config.Commands); err != nil {
 		return params, err
@@ -2789,8 +2988,8 @@ func (config DeleteMyCommandsConfig) method() string {
 	return "deleteMyCommands"
 }
 
-func (config DeleteMyCommandsConfig) params() (Params, error) {
-	params := make(Params)
+func (config DeleteMyCommandsConfig) params() (*configParams, error) {
+	params := newParams()
 
 	err := params.AddInterface("scope", config.Scope)
 	params.AddNonEmpty("language_code", config.LanguageCode)
@@ -2811,8 +3010,8 @@ func (config SetChatMenuButtonConfig) method() string {
 	return "setChatMenuButton"
 }
 
-func (config SetChatMenuButtonConfig) params() (Params, error) {
-	params := make(Params)
+func (config SetChatMenuButtonConfig) params() (*configParams, error) {
+	params := newParams()
 
 	if err := params.AddFirstValid("chat_id", config.ChatID, config.ChannelUsername); err != nil {
 		return params, err
@@ -2831,8 +3030,8 @@ func (config GetChatMenuButtonConfig) method() string {
 	return "getChatMenuButton"
 }
 
-func (config GetChatMenuButtonConfig) params() (Params, error) {
-	params := make(Params)
+func (config GetChatMenuButtonConfig) params() (*configParams, error) {
+	params := newParams()
 
 	err := params.AddFirstValid("chat_id", config.ChatID, config.ChannelUsername)
 
@@ -2848,8 +3047,8 @@ func (config SetMyDefaultAdministratorRightsConfig) method() string {
 	return "setMyDefaultAdministratorRights"
 }
 
-func (config SetMyDefaultAdministratorRightsConfig) params() (Params, error) {
-	params := make(Params)
+func (config SetMyDefaultAdministratorRightsConfig) params() (*configParams, error) {
+	params := newParams()
 
 	err := params.AddInterface("rights", config.Rights)
 	params.AddBool("for_channels", config.ForChannels)
@@ -2865,8 +3064,8 @@ func (config GetMyDefaultAdministratorRightsConfig) method() string {
 	return "getMyDefaultAdministratorRights"
 }
 
-func (config GetMyDefaultAdministratorRightsConfig) params() (Params, error) {
-	params := make(Params)
+func (config GetMyDefaultAdministratorRightsConfig) params() (*configParams, error) {
+	params := newParams()
 
 	params.AddBool("for_channels", config.ForChannels)
 
@@ -2910,6 +3109,16 @@ func prepareInputMediaParam(inputMedia interface{}, idx int) interface{} {
 			m.Thumb = fileAttach(fmt.Sprintf("attach://file-%d-thumb", idx))
 		}
 
+		return m
+	case InputMediaAnimation:
+		if m.Media.NeedsUpload() {
+			m.Media = fileAttach(fmt.Sprintf("attach://file-%d", idx))
+		}
+
+		if m.Thumb != nil && m.Thumb.NeedsUpload() {
+			m.Thumb = fileAttach(fmt.Sprintf("attach://file-%d-thumb", idx))
+		}
+
 		return m
 	case InputMediaDocument:
 		if m.Media.NeedsUpload() {
@@ -2954,8 +3163,22 @@ func prepareInputMediaFile(inputMedia interface{}, idx int) []RequestFile {
 		}
 
 		if m.Thumb != nil && m.Thumb.NeedsUpload() {
+			files = append(files, RequestFile{
+				Name: fmt.Sprintf("file-%d-thumb", idx),
+				Data: m.Thumb,
+			})
+		}
+	case InputMediaAnimation:
+		if m.Media.NeedsUpload() {
 			files = append(files, RequestFile{
 				Name: fmt.Sprintf("file-%d", idx),
+				Data: m.Media,
+			})
+		}
+
+		if m.Thumb != nil && m.Thumb.NeedsUpload() {
+			files = append(files, RequestFile{
+				Name: fmt.Sprintf("file-%d-thumb", idx),
 				Data: m.Thumb,
 			})
 		}
@@ -2969,7 +3192,7 @@ func prepareInputMediaFile(inputMedia interface{}, idx int) []RequestFile {
 
 		if m.Thumb != nil && m.Thumb.NeedsUpload() {
 			files = append(files, RequestFile{
-				Name: fmt.Sprintf("file-%d", idx),
+				Name: fmt.Sprintf("file-%d-thumb", idx),
 				Data: m.Thumb,
 			})
 		}
@@ -2983,7 +3206,7 @@ func prepareInputMediaFile(inputMedia interface{}, idx int) []RequestFile {
 
 		if m.Thumb != nil && m.Thumb.NeedsUpload() {
 			files = append(files, RequestFile{
-				Name: fmt.Sprintf("file-%d", idx),
+				Name: fmt.Sprintf("file-%d-thumb", idx),
 				Data: m.Thumb,
 			})
 		}
@@ -3043,8 +3266,8 @@ func (config ConvertGiftToStarsConfig) method() string {
 	return "convertGiftToStars"
 }
 
-func (config ConvertGiftToStarsConfig) params() (Params, error) {
-	params := Params{}
+func (config ConvertGiftToStarsConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonEmpty("business_connection_id", config.BusinessConnectionID)
 	params.AddNonEmpty("owned_gift_id", config.OwnedGiftID)
 	return params, nil
@@ -3080,8 +3303,8 @@ func (config UpgradeGiftConfig) method() string {
 	return "upgradeGift"
 }
 
-func (config UpgradeGiftConfig) params() (Params, error) {
-	params := Params{}
+func (config UpgradeGiftConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonEmpty("business_connection_id", config.BusinessConnectionID)
 	params.AddNonEmpty("owned_gift_id", config.OwnedGiftID)
 	params.AddBool("keep_original_details", config.KeepOriginalDetails)
@@ -3100,8 +3323,8 @@ func (config TransferGiftConfig) method() string {
 	return "transferGift"
 }
 
-func (config TransferGiftConfig) params() (Params, error) {
-	params := Params{}
+func (config TransferGiftConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonEmpty("business_connection_id", config.BusinessConnectionID)
 	params.AddNonEmpty("owned_gift_id", config.OwnedGiftID)
 	params.AddNonZero64("new_owner_chat_id", config.NewOwnerChatID)
@@ -111,8 +111,8 @@ type RemoveChatVerificationConfig struct {
 	ChatID string
 }
 
-func (config RemoveChatVerificationConfig) params() (Params, error) {
-	params := Params{}
+func (config RemoveChatVerificationConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonEmpty("chat_id", config.ChatID)
 	return params, nil
 }
@@ -127,8 +127,8 @@ type ReadBusinessMessageConfig struct {
 	MessageID            int
 }
 
-func (config ReadBusinessMessageConfig) params() (Params, error) {
-	params := Params{}
+func (config ReadBusinessMessageConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonEmpty("business_connection_id", config.BusinessConnectionID)
 	params.AddNonEmpty("chat_id", config.ChatID)
 	params.AddNonZero("message_id", config.MessageID)
@@ -144,8 +144,8 @@ type DeleteBusinessMessagesConfig struct {
 	MessageIDs           []int
 }
 
-func (config DeleteBusinessMessagesConfig) params() (Params, error) {
-	params := Params{}
+func (config DeleteBusinessMessagesConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonEmpty("business_connection_id", config.BusinessConnectionID)
 	params.AddInterface("message_ids", config.MessageIDs)
 	return params, nil
@@ -161,8 +161,8 @@ type SetBusinessAccountNameConfig struct {
 	LastName             string
 }
 
-func (config SetBusinessAccountNameConfig) params() (Params, error) {
-	params := Params{}
+func (config SetBusinessAccountNameConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonEmpty("business_connection_id", config.BusinessConnectionID)
 	params.AddNonEmpty("first_name", config.FirstName)
 	params.AddNonEmpty("last_name", config.LastName)
@@ -178,8 +178,8 @@ type SetBusinessAccountUsernameConfig struct {
 	Username             string
 }
 
-func (config SetBusinessAccountUsernameConfig) params() (Params, error) {
-	params := Params{}
+func (config SetBusinessAccountUsernameConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonEmpty("business_connection_id", config.BusinessConnectionID)
 	params.AddNonEmpty("username", config.Username)
 	return params, nil
@@ -194,8 +194,8 @@ type SetBusinessAccountBioConfig struct {
 	Bio                  string
 }
 
-func (config SetBusinessAccountBioConfig) params() (Params, error) {
-	params := Params{}
+func (config SetBusinessAccountBioConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonEmpty("business_connection_id", config.BusinessConnectionID)
 	params.AddNonEmpty("bio", config.Bio)
 	return params, nil
@@ -211,8 +211,8 @@ type SetBusinessAccountProfilePhotoConfig struct {
 	IsPublic             bool
 }
 
-func (config SetBusinessAccountProfilePhotoConfig) params() (Params, error) {
-	params := Params{}
+func (config SetBusinessAccountProfilePhotoConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonEmpty("business_connection_id", config.BusinessConnectionID)
 	params.AddInterface("photo", config.Photo)
 	params.AddBool("is_public", config.IsPublic)
@@ -224,8 +224,8 @@ type RemoveBusinessAccountProfilePhotoConfig struct {
 	IsPublic             bool
 }
 
-func (config RemoveBusinessAccountProfilePhotoConfig) params() (Params, error) {
-	params := Params{}
+func (config RemoveBusinessAccountProfilePhotoConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonEmpty("business_connection_id", config.BusinessConnectionID)
 	params.AddBool("is_public", config.IsPublic)
 	return params, nil
@@ -241,10 +241,10 @@ type SetBusinessAccountGiftSettingsConfig struct {
 	AcceptedGiftTypes    AcceptedGiftTypes
 }
 
-func (config SetBusinessAccountGiftSettingsConfig) params() (Params, error) {
-	params := Params{}
+func (config SetBusinessAccountGiftSettingsConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonEmpty("business_connection_id", config.BusinessConnectionID)
-	params.AddBool("show_gift_button", config.ShowGiftButton)
+	params.SetBool("show_gift_button", config.ShowGiftButton)
 	params.AddInterface("accepted_gift_types", config.AcceptedGiftTypes)
 	return params, nil
 }
@@ -257,8 +257,8 @@ type GetBusinessAccountStarBalanceConfig struct {
 	BusinessConnectionID string
 }
 
-func (config GetBusinessAccountStarBalanceConfig) params() (Params, error) {
-	params := Params{}
+func (config GetBusinessAccountStarBalanceConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonEmpty("business_connection_id", config.BusinessConnectionID)
 	return params, nil
 }
@@ -279,8 +279,8 @@ type GetBusinessAccountGiftsConfig struct {
 	Limit                int
 }
 
-func (config GetBusinessAccountGiftsConfig) params() (Params, error) {
-	params := Params{}
+func (config GetBusinessAccountGiftsConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonEmpty("business_connection_id", config.BusinessConnectionID)
 	params.AddBool("exclude_unsaved", config.ExcludeUnsaved)
 	params.AddBool("exclude_saved", config.ExcludeSaved)
@@ -302,8 +302,8 @@ type TransferBusinessAccountStarsConfig struct {
 	StarCount            int
 }
 
-func (config TransferBusinessAccountStarsConfig) params() (Params, error) {
-	params := Params{}
+func (config TransferBusinessAccountStarsConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonEmpty("business_connection_id", config.BusinessConnectionID)
 	params.AddNonZero("star_count", config.StarCount)
 	return params, nil
@@ -22,7 +22,7 @@ type SendChecklistConfig struct {
 	ReplyParameters ReplyParameters `json:"reply_parameters"`
 }
 
-func (config SendChecklistConfig) params() (Params, error) {
+func (config SendChecklistConfig) params() (*configParams, error) {
 	params, err := config.BaseChat.params()
 	if err != nil {
 		return nil, err
@@ -48,7 +48,7 @@ type EditMessageChecklistConfig struct {
 	Checklist Checklist `json:"checklist"`
 }
 
-func (config EditMessageChecklistConfig) params() (Params, error) {
+func (config EditMessageChecklistConfig) params() (*configParams, error) {
 	params, err := config.BaseChat.params()
 	if err != nil {
 		return nil, err
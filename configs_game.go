@@ -32,7 +32,7 @@ func (config SendGameConfig) method() string {
 	return "sendGame"
 }
 
-func (config SendGameConfig) params() (Params, error) {
+func (config SendGameConfig) params() (*configParams, error) {
 	params, _ := config.BaseChat.params()
 	params.AddNonEmpty("business_connection_id", config.BusinessConnectionID)
 	params.AddNonEmpty("game_short_name", config.GameShortName)
@@ -62,15 +62,15 @@ type SetGameScoreConfig struct {
 	InlineMessageID    string
 }
 
-func (config SetGameScoreConfig) params() (Params, error) {
-	params := make(Params)
+func (config SetGameScoreConfig) params() (*configParams, error) {
+	params := newParams()
 
 	params.AddNonZero64("user_id", config.UserID)
-	params.AddNonZero("scrore", config.Score)
+	params.SetInt("score", config.Score)
 	params.AddBool("disable_edit_message", config.DisableEditMessage)
 
 	if config.InlineMessageID != "" {
-		params["inline_message_id"] = config.InlineMessageID
+		params.Params["inline_message_id"] = config.InlineMessageID
 	} else {
 		params.AddFirstValid("chat_id", config.ChatID, config.ChannelUsername)
 		params.AddNonZero("message_id", config.MessageID)
@@ -96,8 +96,8 @@ type GetGameHighScoresConfig struct {
 	InlineMessageID string
 }
 
-func (config GetGameHighScoresConfig) params() (Params, error) {
-	params := make(Params)
+func (config GetGameHighScoresConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonZero64("user_id", config.UserID)
 	params.AddNonEmpty("inline_message_id", config.InlineMessageID)
 
@@ -425,8 +425,8 @@ func (config SavePreparedInlineMessageConfig) method() string {
 	return "savePreparedInlineMessage"
 }
 
-func (config SavePreparedInlineMessageConfig) params() (Params, error) {
-	params := make(Params)
+func (config SavePreparedInlineMessageConfig) params() (*configParams, error) {
+	params := newParams()
 
 	params.AddNonZero64("user_id", config.UserID)
 	params.AddInterface("result", config.Result)
@@ -20,8 +20,8 @@ func (config SetPassportDataErrorsConfig) method() string {
 	return "setPassportDataErrors"
 }
 
-func (config SetPassportDataErrorsConfig) params() (Params, error) {
-	params := Params{}
+func (config SetPassportDataErrorsConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonZero64("user_id", config.UserID)
 	params.AddInterface("errors", config.Errors)
 	return params, nil
@@ -65,17 +65,17 @@ type InvoiceConfig struct {
 	ReplyParameters           interface{}
 }
 
-func (config InvoiceConfig) params() (Params, error) {
+func (config InvoiceConfig) params() (*configParams, error) {
 	params, err := config.BaseChat.params()
 	if err != nil {
 		return params, err
 	}
 
-	params["title"] = config.Title
-	params["description"] = config.Description
-	params["payload"] = config.Payload
-	params["provider_token"] = config.ProviderToken
-	params["currency"] = config.Currency
+	params.Params["title"] = config.Title
+	params.Params["description"] = config.Description
+	params.Params["payload"] = config.Payload
+	params.Params["provider_token"] = config.ProviderToken
+	params.Params["currency"] = config.Currency
 	if err = params.AddInterface("prices", config.Prices); err != nil {
 		return params, err
 	}
@@ -161,8 +161,8 @@ type InvoiceLinkConfig struct {
 	IsFlexible                bool
 }
 
-func (config InvoiceLinkConfig) params() (Params, error) {
-	params := Params{}
+func (config InvoiceLinkConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonEmpty("business_connection_id", config.BusinessConnectionID)
 	params.AddNonEmpty("title", config.Title)
 	params.AddNonEmpty("description", config.Description)
@@ -211,8 +211,8 @@ func (config GetStarTransactionsConfig) method() string {
 	return "getStarTransactions"
 }
 
-func (config GetStarTransactionsConfig) params() (Params, error) {
-	params := Params{}
+func (config GetStarTransactionsConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonZero("offset", config.Offset)
 	params.AddNonZero("limit", config.Limit)
 	return params, nil
@@ -237,8 +237,8 @@ func (config RefundStarPaymentConfig) method() string {
 	return "refundStarPayment"
 }
 
-func (config RefundStarPaymentConfig) params() (Params, error) {
-	params := Params{}
+func (config RefundStarPaymentConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonZero64("user_id", config.UserID)
 	params.AddNonEmpty("telegram_payment_charge_id", config.TelegramPaymentChargeID)
 	return params, nil
@@ -265,11 +265,11 @@ func (config EditUserStarSubscriptionConfig) method() string {
 	return "editUserStarSubscription"
 }
 
-func (config EditUserStarSubscriptionConfig) params() (Params, error) {
-	params := Params{}
+func (config EditUserStarSubscriptionConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonZero64("user_id", config.UserID)
 	params.AddNonEmpty("telegram_payment_charge_id", config.TelegramPaymentChargeID)
-	params.AddBool("is_canceled", config.IsCanceled)
+	params.SetBool("is_canceled", config.IsCanceled)
 	return params, nil
 }
 
@@ -279,6 +279,6 @@ func (config GetMyStarBalanceConfig) method() string {
 	return "getMyStarBalance"
 }
 
-func (config GetMyStarBalanceConfig) params() (Params, error) {
-	return Params{}, nil
+func (config GetMyStarBalanceConfig) params() (*configParams, error) {
+	return newParams(), nil
 }
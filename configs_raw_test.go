@@ -0,0 +1,97 @@
+package tgbotapi
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestRawRequest(t *testing.T) {
+	var method, text, field string
+
+	bot := newLocalBot(t, func(w http.ResponseWriter, r *http.Request) {
+		method = r.URL.Path[len("/bot123456:local-test-token/"):]
+
+		if err := r.ParseMultipartForm(1 << 20); err == nil {
+			file, _, err := r.FormFile("document")
+			if err != nil {
+				t.Errorf("expected an uploaded document: %v", err)
+			} else {
+				data, _ := io.ReadAll(file)
+				field = string(data)
+			}
+		}
+		text = r.FormValue("text")
+
+		writeResult(w, `{"message_id":3,"text":"`+text+`"}`)
+	})
+
+	var seen string
+	bot.Use(func(next RequestHandler) RequestHandler {
+		return func(ctx context.Context, req *APIRequest) (*APIResponse, error) {
+			seen = req.Endpoint
+			return next(ctx, req)
+		}
+	})
+
+	params := Params{"chat_id": "1", "text": "Hello"}
+	msg, err := Call[Message](bot, RawRequest{Method: "sendFutureMessage", Params: params})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if method != "sendFutureMessage" || seen != "sendFutureMessage" || text != "Hello" || msg.MessageID != 3 {
+		t.Errorf("unexpected request %s (%s) with text %q, got %+v", method, seen, text, msg)
+	}
+
+	_, err = bot.Request(RawRequest{
+		Method: "sendFutureDocument",
+		Params: Params{"chat_id": "1"},
+		Files:  []RequestFile{{Name: "document", Data: FileBytes{Name: "a.txt", Bytes: []byte("data")}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if method != "sendFutureDocument" || field != "data" {
+		t.Errorf("expected an upload to sendFutureDocument, got %s with %q", method, field)
+	}
+
+	if len(params) != 2 {
+		t.Errorf("params of the raw request were changed: %v", params)
+	}
+
+	if _, err := bot.Request(RawRequest{}); err == nil {
+		t.Error("expected an error for a request without a method")
+	}
+}
+
+func TestRawRequestWriteToHTTPResponse(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	err := WriteToHTTPResponse(w, RawRequest{
+		Method: "sendFutureMessage",
+		Params: Params{"chat_id": "1"},
+		Files:  []RequestFile{{Name: "photo", Data: FileID("id")}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values, err := url.ParseQuery(w.Body.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values.Get("method") != "sendFutureMessage" || values.Get("chat_id") != "1" || values.Get("photo") != "id" {
+		t.Errorf("unexpected response body %q", w.Body.String())
+	}
+
+	err = WriteToHTTPResponse(httptest.NewRecorder(), RawRequest{
+		Method: "sendFutureMessage",
+		Files:  []RequestFile{{Name: "photo", Data: FileBytes{Name: "a", Bytes: []byte("a")}}},
+	})
+	if err == nil {
+		t.Error("expected an error when uploading files in a response")
+	}
+}
@@ -1,5 +1,7 @@
 package tgbotapi
 
+import "fmt"
+
 /**
 Stickers
 The following methods and objects allow your bot to handle stickers and sticker sets.
@@ -197,8 +199,8 @@ func (config GetStickerSetConfig) method() string {
 	return "getStickerSet"
 }
 
-func (config GetStickerSetConfig) params() (Params, error) {
-	params := Params{}
+func (config GetStickerSetConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonEmpty("name", config.Name)
 	return params, nil
 }
@@ -212,8 +214,8 @@ func (config GetCustomEmojiStickersConfig) method() string {
 	return "getCustomEmojiStickers"
 }
 
-func (config GetCustomEmojiStickersConfig) params() (Params, error) {
-	params := Params{}
+func (config GetCustomEmojiStickersConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddInterface("custom_emoji_ids", config.CustomEmojiIDs)
 	return params, nil
 }
@@ -229,8 +231,8 @@ func (config UploadStickerFileConfig) method() string {
 	return "uploadStickerFile"
 }
 
-func (config UploadStickerFileConfig) params() (Params, error) {
-	params := Params{}
+func (config UploadStickerFileConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonZero64("user_id", config.UserID)
 	params.AddNonEmpty("sticker_format", config.StickerFormat)
 	return params, nil
@@ -243,13 +245,41 @@ func (config UploadStickerFileConfig) files() []RequestFile {
 	}}
 }
 
-// InputSticker represents a sticker to be added to a sticker set.
+// InputSticker represents a sticker to be added to a sticker set. Stickers
+// that need to be uploaded are attached to the request as files.
 type InputSticker struct {
-	Sticker      RequestFileData
-	Format       string
-	EmojiList    []string
-	MaskPosition *MaskPosition
-	Keywords     []string
+	Sticker      RequestFileData `json:"sticker"`
+	Format       string          `json:"format"`
+	EmojiList    []string        `json:"emoji_list"`
+	MaskPosition *MaskPosition   `json:"mask_position,omitempty"`
+	Keywords     []string        `json:"keywords,omitempty"`
+}
+
+// prepareInputStickerParam replaces the sticker file with an attach:// name
+// if it needs to be uploaded. The idx is formatted into "attach://sticker-%d".
+//
+// It is expected to be used in conjunction with prepareInputStickerFile.
+func prepareInputStickerParam(sticker InputSticker, idx int) InputSticker {
+	if sticker.Sticker != nil && sticker.Sticker.NeedsUpload() {
+		sticker.Sticker = fileAttach(fmt.Sprintf("attach://sticker-%d", idx))
+	}
+
+	return sticker
+}
+
+// prepareInputStickerFile returns the file to upload for the sticker, named
+// "sticker-%d", or nil if it doesn't need to be uploaded.
+//
+// It is expected to be used in conjunction with prepareInputStickerParam.
+func prepareInputStickerFile(sticker InputSticker, idx int) []RequestFile {
+	if sticker.Sticker == nil || !sticker.Sticker.NeedsUpload() {
+		return nil
+	}
+
+	return []RequestFile{{
+		Name: fmt.Sprintf("sticker-%d", idx),
+		Data: sticker.Sticker,
+	}}
 }
 
 // CreateNewStickerSetConfig contains information about a CreateNewStickerSet request.
@@ -266,17 +296,29 @@ func (config CreateNewStickerSetConfig) method() string {
 	return "createNewStickerSet"
 }
 
-func (config CreateNewStickerSetConfig) params() (Params, error) {
-	params := Params{}
+func (config CreateNewStickerSetConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonZero64("user_id", config.UserID)
 	params.AddNonEmpty("name", config.Name)
 	params.AddNonEmpty("title", config.Title)
-	params.AddInterface("stickers", config.Stickers)
+	stickers := make([]InputSticker, len(config.Stickers))
+	for idx, sticker := range config.Stickers {
+		stickers[idx] = prepareInputStickerParam(sticker, idx)
+	}
+	params.AddInterface("stickers", stickers)
 	params.AddNonEmpty("sticker_type", config.StickerType)
 	params.AddBool("needs_repainting", config.NeedsRepainting)
 	return params, nil
 }
 
+func (config CreateNewStickerSetConfig) files() []RequestFile {
+	files := []RequestFile{}
+	for idx, sticker := range config.Stickers {
+		files = append(files, prepareInputStickerFile(sticker, idx)...)
+	}
+	return files
+}
+
 // AddStickerToSetConfig contains information about an AddStickerToSet request.
 type AddStickerToSetConfig struct {
 	UserID  int64
@@ -288,14 +330,18 @@ func (config AddStickerToSetConfig) method() string {
 	return "addStickerToSet"
 }
 
-func (config AddStickerToSetConfig) params() (Params, error) {
-	params := Params{}
+func (config AddStickerToSetConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonZero64("user_id", config.UserID)
 	params.AddNonEmpty("name", config.Name)
-	params.AddInterface("sticker", config.Sticker)
+	params.AddInterface("sticker", prepareInputStickerParam(config.Sticker, 0))
 	return params, nil
 }
 
+func (config AddStickerToSetConfig) files() []RequestFile {
+	return prepareInputStickerFile(config.Sticker, 0)
+}
+
 // SetStickerPositionInSetConfig contains information about a SetStickerPositionInSet request.
 type SetStickerPositionInSetConfig struct {
 	Sticker  string
@@ -306,10 +352,10 @@ func (config SetStickerPositionInSetConfig) method() string {
 	return "setStickerPositionInSet"
 }
 
-func (config SetStickerPositionInSetConfig) params() (Params, error) {
-	params := Params{}
+func (config SetStickerPositionInSetConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonEmpty("sticker", config.Sticker)
-	params.AddNonZero("position", config.Position)
+	params.SetInt("position", config.Position)
 	return params, nil
 }
 
@@ -322,8 +368,8 @@ func (config DeleteStickerFromSetConfig) method() string {
 	return "deleteStickerFromSet"
 }
 
-func (config DeleteStickerFromSetConfig) params() (Params, error) {
-	params := Params{}
+func (config DeleteStickerFromSetConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonEmpty("sticker", config.Sticker)
 	return params, nil
 }
@@ -340,15 +386,19 @@ func (config ReplaceStickerInSetConfig) method() string {
 	return "replaceStickerInSet"
 }
 
-func (config ReplaceStickerInSetConfig) params() (Params, error) {
-	params := Params{}
+func (config ReplaceStickerInSetConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonZero64("user_id", config.UserID)
 	params.AddNonEmpty("name", config.Name)
 	params.AddNonEmpty("old_sticker", config.OldSticker)
-	params.AddInterface("sticker", config.Sticker)
+	params.AddInterface("sticker", prepareInputStickerParam(config.Sticker, 0))
 	return params, nil
 }
 
+func (config ReplaceStickerInSetConfig) files() []RequestFile {
+	return prepareInputStickerFile(config.Sticker, 0)
+}
+
 // SetStickerEmojiListConfig contains information about a SetStickerEmojiList request.
 type SetStickerEmojiListConfig struct {
 	Sticker   string
@@ -359,8 +409,8 @@ func (config SetStickerEmojiListConfig) method() string {
 	return "setStickerEmojiList"
 }
 
-func (config SetStickerEmojiListConfig) params() (Params, error) {
-	params := Params{}
+func (config SetStickerEmojiListConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonEmpty("sticker", config.Sticker)
 	params.AddInterface("emoji_list", config.EmojiList)
 	return params, nil
@@ -376,8 +426,8 @@ func (config SetStickerKeywordsConfig) method() string {
 	return "setStickerKeywords"
 }
 
-func (config SetStickerKeywordsConfig) params() (Params, error) {
-	params := Params{}
+func (config SetStickerKeywordsConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonEmpty("sticker", config.Sticker)
 	params.AddInterface("keywords", config.Keywords)
 	return params, nil
@@ -393,8 +443,8 @@ func (config SetStickerMaskPositionConfig) method() string {
 	return "setStickerMaskPosition"
 }
 
-func (config SetStickerMaskPositionConfig) params() (Params, error) {
-	params := Params{}
+func (config SetStickerMaskPositionConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonEmpty("sticker", config.Sticker)
 	params.AddInterface("mask_position", config.MaskPosition)
 	return params, nil
@@ -410,8 +460,8 @@ func (config SetStickerSetTitleConfig) method() string {
 	return "setStickerSetTitle"
 }
 
-func (config SetStickerSetTitleConfig) params() (Params, error) {
-	params := Params{}
+func (config SetStickerSetTitleConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonEmpty("name", config.Name)
 	params.AddNonEmpty("title", config.Title)
 	return params, nil
@@ -429,8 +479,8 @@ func (config SetStickerSetThumbnailConfig) method() string {
 	return "setStickerSetThumbnail"
 }
 
-func (config SetStickerSetThumbnailConfig) params() (Params, error) {
-	params := Params{}
+func (config SetStickerSetThumbnailConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonEmpty("name", config.Name)
 	params.AddNonZero64("user_id", config.UserID)
 	params.AddNonEmpty("format", config.Format)
@@ -454,8 +504,8 @@ func (config SetCustomEmojiStickerSetThumbnailConfig) method() string {
 	return "setCustomEmojiStickerSetThumbnail"
 }
 
-func (config SetCustomEmojiStickerSetThumbnailConfig) params() (Params, error) {
-	params := Params{}
+func (config SetCustomEmojiStickerSetThumbnailConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonEmpty("name", config.Name)
 	params.AddNonEmpty("custom_emoji_id", config.CustomEmojiID)
 	return params, nil
@@ -470,8 +520,8 @@ func (config DeleteStickerSetConfig) method() string {
 	return "deleteStickerSet"
 }
 
-func (config DeleteStickerSetConfig) params() (Params, error) {
-	params := Params{}
+func (config DeleteStickerSetConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonEmpty("name", config.Name)
 	return params, nil
 }
@@ -494,12 +544,18 @@ func (config SendStickerConfig) method() string {
 	return "sendSticker"
 }
 
-func (config SendStickerConfig) params() (Params, error) {
-	params := Params{}
+func (config SendStickerConfig) files() []RequestFile {
+	return []RequestFile{{
+		Name: "sticker",
+		Data: config.Sticker,
+	}}
+}
+
+func (config SendStickerConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonEmpty("business_connection_id", config.BusinessConnectionID)
 	params.AddNonZero64("chat_id", config.ChatID)
 	params.AddNonZero64("message_thread_id", config.MessageThreadID)
-	params.AddInterface("sticker", config.Sticker)
 	params.AddNonEmpty("emoji", config.Emoji)
 	params.AddBool("disable_notification", config.DisableNotification)
 	params.AddBool("protect_content", config.ProtectContent)
@@ -533,8 +589,8 @@ func (config InputStickerConfig) method() string {
 	return "inputSticker"
 }
 
-func (config InputStickerConfig) params() (Params, error) {
-	params := Params{}
+func (config InputStickerConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonEmpty("sticker", config.Sticker)
 	params.AddNonEmpty("format", config.Format)
 	params.AddInterface("emoji_list", config.EmojiList)
@@ -553,8 +609,8 @@ func (config SetChatStickerSetConfig) method() string {
 	return "setChatStickerSet"
 }
 
-func (config SetChatStickerSetConfig) params() (Params, error) {
-	params := Params{}
+func (config SetChatStickerSetConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddFirstValid("chat_id", config.ChatID, config.ChannelUsername)
 	params.AddNonEmpty("sticker_set_name", config.StickerSetName)
 	return params, nil
@@ -569,8 +625,8 @@ func (config DeleteChatStickerSetConfig) method() string {
 	return "deleteChatStickerSet"
 }
 
-func (config DeleteChatStickerSetConfig) params() (Params, error) {
-	params := Params{}
+func (config DeleteChatStickerSetConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddFirstValid("chat_id", config.ChatID, config.ChannelUsername)
 	return params, nil
 }
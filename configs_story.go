@@ -135,8 +135,8 @@ func (config PostStoryConfig) method() string {
 	return "postStory"
 }
 
-func (config PostStoryConfig) params() (Params, error) {
-	params := Params{}
+func (config PostStoryConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonEmpty("business_connection_id", config.BusinessConnectionID)
 	params.AddInterface("content", config.Content)
 	params.AddNonZero("active_period", config.ActivePeriod)
@@ -144,8 +144,8 @@ func (config PostStoryConfig) params() (Params, error) {
 	params.AddNonEmpty("parse_mode", config.ParseMode)
 	params.AddInterface("caption_entities", config.CaptionEntities)
 	params.AddInterface("areas", config.Areas)
-	params.AddBool("post_to_chat_page", config.PostToChatPage)
-	params.AddBool("protect_content", config.ProtectContent)
+	params.SetBool("post_to_chat_page", config.PostToChatPage)
+	params.SetBool("protect_content", config.ProtectContent)
 
 	err := params.CheckArgs("business_connection_id", "content", "active_period")
 	if err != nil {
@@ -170,8 +170,8 @@ func (config EditStoryConfig) method() string {
 	return "editStory"
 }
 
-func (config EditStoryConfig) params() (Params, error) {
-	params := Params{}
+func (config EditStoryConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonEmpty("business_connection_id", config.BusinessConnectionID)
 	params.AddNonZero("story_id", config.StoryID)
 	params.AddInterface("content", config.Content)
@@ -179,8 +179,8 @@ func (config EditStoryConfig) params() (Params, error) {
 	params.AddNonEmpty("parse_mode", config.ParseMode)
 	params.AddInterface("caption_entities", config.CaptionEntities)
 	params.AddInterface("areas", config.Areas)
-	params.AddBool("post_to_chat_page", config.PostToChatPage)
-	params.AddBool("protect_content", config.ProtectContent)
+	params.SetBool("post_to_chat_page", config.PostToChatPage)
+	params.SetBool("protect_content", config.ProtectContent)
 
 	err := params.CheckArgs("business_connection_id", "story_id", "content")
 	if err != nil {
@@ -198,8 +198,8 @@ func (config DeleteStoryConfig) method() string {
 	return "deleteStory"
 }
 
-func (config DeleteStoryConfig) params() (Params, error) {
-	params := Params{}
+func (config DeleteStoryConfig) params() (*configParams, error) {
+	params := newParams()
 	params.AddNonEmpty("business_connection_id", config.BusinessConnectionID)
 	params.AddNonZero("story_id", config.StoryID)
 	err := params.CheckArgs("business_connection_id", "story_id")
@@ -61,28 +61,28 @@ func TestPostStoryConfig(t *testing.T) {
 	}
 
 	// Check that all fields are properly set in params
-	if params["business_connection_id"] != "test_connection" {
-		t.Errorf("Expected business_connection_id to be 'test_connection', got '%s'", params["business_connection_id"])
+	if params.Params["business_connection_id"] != "test_connection" {
+		t.Errorf("Expected business_connection_id to be 'test_connection', got '%s'", params.Params["business_connection_id"])
 	}
 
-	if params["active_period"] != "86400" {
-		t.Errorf("Expected active_period to be '86400', got '%s'", params["active_period"])
+	if params.Params["active_period"] != "86400" {
+		t.Errorf("Expected active_period to be '86400', got '%s'", params.Params["active_period"])
 	}
 
-	if params["caption"] != "Test story caption" {
-		t.Errorf("Expected caption to be 'Test story caption', got '%s'", params["caption"])
+	if params.Params["caption"] != "Test story caption" {
+		t.Errorf("Expected caption to be 'Test story caption', got '%s'", params.Params["caption"])
 	}
 
-	if params["parse_mode"] != "Markdown" {
-		t.Errorf("Expected parse_mode to be 'Markdown', got '%s'", params["parse_mode"])
+	if params.Params["parse_mode"] != "Markdown" {
+		t.Errorf("Expected parse_mode to be 'Markdown', got '%s'", params.Params["parse_mode"])
 	}
 
-	if params["post_to_chat_page"] != "true" {
-		t.Errorf("Expected post_to_chat_page to be 'true', got '%s'", params["post_to_chat_page"])
+	if params.Params["post_to_chat_page"] != "true" {
+		t.Errorf("Expected post_to_chat_page to be 'true', got '%s'", params.Params["post_to_chat_page"])
 	}
 
-	if params["protect_content"] != "false" {
-		t.Errorf("Expected protect_content to be 'false', got '%s'", params["protect_content"])
+	if params.Params["protect_content"] != "false" {
+		t.Errorf("Expected protect_content to be 'false', got '%s'", params.Params["protect_content"])
 	}
 }
 
@@ -141,28 +141,28 @@ func TestEditStoryConfig(t *testing.T) {
 	}
 
 	// Check that all fields are properly set in params
-	if params["business_connection_id"] != "test_connection" {
-		t.Errorf("Expected business_connection_id to be 'test_connection', got '%s'", params["business_connection_id"])
+	if params.Params["business_connection_id"] != "test_connection" {
+		t.Errorf("Expected business_connection_id to be 'test_connection', got '%s'", params.Params["business_connection_id"])
 	}
 
-	if params["story_id"] != "12345" {
-		t.Errorf("Expected story_id to be '12345', got '%s'", params["story_id"])
+	if params.Params["story_id"] != "12345" {
+		t.Errorf("Expected story_id to be '12345', got '%s'", params.Params["story_id"])
 	}
 
-	if params["caption"] != "Updated story caption" {
-		t.Errorf("Expected caption to be 'Updated story caption', got '%s'", params["caption"])
+	if params.Params["caption"] != "Updated story caption" {
+		t.Errorf("Expected caption to be 'Updated story caption', got '%s'", params.Params["caption"])
 	}
 
-	if params["parse_mode"] != "HTML" {
-		t.Errorf("Expected parse_mode to be 'HTML', got '%s'", params["parse_mode"])
+	if params.Params["parse_mode"] != "HTML" {
+		t.Errorf("Expected parse_mode to be 'HTML', got '%s'", params.Params["parse_mode"])
 	}
 
-	if params["post_to_chat_page"] != "false" {
-		t.Errorf("Expected post_to_chat_page to be 'false', got '%s'", params["post_to_chat_page"])
+	if params.Params["post_to_chat_page"] != "false" {
+		t.Errorf("Expected post_to_chat_page to be 'false', got '%s'", params.Params["post_to_chat_page"])
 	}
 
-	if params["protect_content"] != "true" {
-		t.Errorf("Expected protect_content to be 'true', got '%s'", params["protect_content"])
+	if params.Params["protect_content"] != "true" {
+		t.Errorf("Expected protect_content to be 'true', got '%s'", params.Params["protect_content"])
 	}
 }
 
@@ -185,11 +185,11 @@ func TestDeleteStoryConfig(t *testing.T) {
 	}
 
 	// Check that all fields are properly set in params
-	if params["business_connection_id"] != "test_connection" {
-		t.Errorf("Expected business_connection_id to be 'test_connection', got '%s'", params["business_connection_id"])
+	if params.Params["business_connection_id"] != "test_connection" {
+		t.Errorf("Expected business_connection_id to be 'test_connection', got '%s'", params.Params["business_connection_id"])
 	}
 
-	if params["story_id"] != "67890" {
-		t.Errorf("Expected story_id to be '67890', got '%s'", params["story_id"])
+	if params.Params["story_id"] != "67890" {
+		t.Errorf("Expected story_id to be '67890', got '%s'", params.Params["story_id"])
 	}
 }
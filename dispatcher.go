@@ -0,0 +1,229 @@
+package tgbotapi
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// UpdateHandler handles an update routed to it by a Dispatcher. The context
+// carries the telegram.update span of the update, and is canceled with the
+// context the update is dispatched with: the one given to Run or
+// DispatchWithContext, or the request's context for webhooks.
+type UpdateHandler func(ctx context.Context, update Update) error
+
+// UpdateMiddleware wraps an UpdateHandler to add behaviour around handling
+// updates, such as logging, authorization or recovering from panics.
+//
+// Middleware may return without calling next to stop the update from being
+// handled.
+type UpdateMiddleware func(next UpdateHandler) UpdateHandler
+
+// Dispatcher routes updates to handlers, replacing a switch over the fields of
+// every update. Routes are tried in the order they were added, and the first
+// one matching the update handles it. Updates matching no route are passed to
+// the fallback handler, if any.
+//
+//	d := tgbotapi.NewDispatcher(bot)
+//	d.Command("start", start)
+//	d.CallbackPrefix("vote:", vote)
+//	d.ContentType(tgbotapi.ContentTypePhoto, photo)
+//	d.Run(ctx, bot.GetUpdatesChan(tgbotapi.NewUpdate(0)))
+//
+// Routes and middleware must be added before updates are dispatched.
+type Dispatcher struct {
+	// ErrorHandler is called with the errors returned by handlers when
+	// updates are dispatched by Run or ServeHTTP. The errors are logged by
+	// default.
+	ErrorHandler func(ctx context.Context, update Update, err error)
+
+	bot        *BotAPI
+	routes     []route
+	middleware []UpdateMiddleware
+	fallback   UpdateHandler
+}
+
+// route is a handler with the filter deciding which updates it handles.
+type route struct {
+	filter  Filter
+	handler UpdateHandler
+}
+
+// NewDispatcher creates a Dispatcher for updates received by bot.
+func NewDispatcher(bot *BotAPI) *Dispatcher {
+	return &Dispatcher{bot: bot}
+}
+
+// Use adds middleware that is called for every update, including the ones
+// passed to the fallback handler. The first middleware added is the outermost
+// one.
+func (d *Dispatcher) Use(middleware ...UpdateMiddleware) {
+	d.middleware = append(d.middleware, middleware...)
+}
+
+// Match adds a route handling the updates matched by filter. The middleware
+// is only called for the updates handled by this route.
+func (d *Dispatcher) Match(filter Filter, handler UpdateHandler, middleware ...UpdateMiddleware) {
+	d.routes = append(d.routes, route{filter: filter, handler: chainUpdateMiddleware(handler, middleware)})
+}
+
+// Handle adds a route handling updates of the given type, one of the
+// UpdateType constants.
+func (d *Dispatcher) Handle(updateType string, handler UpdateHandler, middleware ...UpdateMiddleware) {
+	d.Match(func(ctx context.Context, update *Update) bool {
+		return update.Type() == updateType
+	}, handler, middleware...)
+}
+
+// Command adds a route handling new messages starting with the command, given
+// without the leading slash. Commands addressed to another bot with the
+// /command@bot syntax are ignored.
+//
+// The bot's username is taken from its Self field, set by NewBotAPI or Init.
+// If it is empty, commands addressed to any bot are handled.
+func (d *Dispatcher) Command(command string, handler UpdateHandler, middleware ...UpdateMiddleware) {
+	d.Match(func(ctx context.Context, update *Update) bool {
+		message := updateMessage(update, true)
+		if message == nil || message.Command() != command {
+			return false
+		}
+
+		_, username, ok := strings.Cut(message.CommandWithAt(), "@")
+		return !ok || d.bot.Self.UserName == "" || strings.EqualFold(username, d.bot.Self.UserName)
+	}, handler, middleware...)
+}
+
+// CallbackPrefix adds a route handling callback queries whose data starts
+// with prefix.
+func (d *Dispatcher) CallbackPrefix(prefix string, handler UpdateHandler, middleware ...UpdateMiddleware) {
+	d.Match(func(ctx context.Context, update *Update) bool {
+		return update.CallbackQuery != nil && strings.HasPrefix(update.CallbackQuery.Data, prefix)
+	}, handler, middleware...)
+}
+
+// Regexp adds a route handling new messages whose text or caption matches re.
+func (d *Dispatcher) Regexp(re *regexp.Regexp, handler UpdateHandler, middleware ...UpdateMiddleware) {
+	d.Match(And(isNewMessage, TextMatches(re)), handler, middleware...)
+}
+
+// ContentType adds a route handling new messages with the content type, one
+// of the ContentType constants.
+func (d *Dispatcher) ContentType(contentType string, handler UpdateHandler, middleware ...UpdateMiddleware) {
+	d.Match(And(isNewMessage, HasContentType(contentType)), handler, middleware...)
+}
+
+// Fallback sets the handler for updates matching no route. Without one, these
+// updates are ignored.
+func (d *Dispatcher) Fallback(handler UpdateHandler, middleware ...UpdateMiddleware) {
+	d.fallback = chainUpdateMiddleware(handler, middleware)
+}
+
+// Dispatch routes the update to its handler, and returns the handler's
+// error.
+func (d *Dispatcher) Dispatch(update Update) error {
+	return d.DispatchWithContext(context.Background(), update)
+}
+
+// DispatchWithContext is like Dispatch but the handler's context is derived
+// from ctx.
+func (d *Dispatcher) DispatchWithContext(ctx context.Context, update Update) error {
+	return d.bot.TraceUpdate(ctx, "", update, func(ctx context.Context) error {
+		return d.handle(ctx, update)
+	})
+}
+
+// handle calls the middleware and the handler of the update with ctx.
+func (d *Dispatcher) handle(ctx context.Context, update Update) error {
+	handler := chainUpdateMiddleware(d.route, d.middleware)
+
+	return handler(ctx, update)
+}
+
+// route is the final UpdateHandler, which calls the handler of the first
+// route matching the update.
+func (d *Dispatcher) route(ctx context.Context, update Update) error {
+	for _, r := range d.routes {
+		if r.filter(ctx, &update) {
+			return r.handler(ctx, update)
+		}
+	}
+
+	if d.fallback != nil {
+		return d.fallback(ctx, update)
+	}
+
+	d.bot.log(ctx, slog.LevelDebug, "unhandled update", slog.Int("update_id", update.UpdateID), slog.String("type", update.Type()))
+
+	return nil
+}
+
+// Run dispatches the updates from the channel one at a time, until the
+// channel is closed or ctx is done. Errors returned by handlers are passed to
+// the ErrorHandler.
+func (d *Dispatcher) Run(ctx context.Context, updates UpdatesChannel) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case update, ok := <-updates:
+			if !ok {
+				return
+			}
+
+			d.dispatch(ctx, "", update)
+		}
+	}
+}
+
+// ServeHTTP dispatches an update received via webhook. The update is handled
+// before responding, so Telegram doesn't send the next update of the chat
+// until it is done. The handler's context is canceled with the request's.
+func (d *Dispatcher) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	update, err := d.bot.HandleUpdate(r)
+	if err != nil {
+		errMsg, _ := json.Marshal(map[string]string{"error": err.Error()})
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write(errMsg)
+		return
+	}
+
+	d.dispatch(r.Context(), UpdateSourceWebhook, *update)
+}
+
+// dispatch dispatches the update from source with ctx and reports the
+// handler's error.
+func (d *Dispatcher) dispatch(ctx context.Context, source string, update Update) {
+	_ = d.bot.TraceUpdate(ctx, source, update, func(ctx context.Context) error {
+		err := d.handle(ctx, update)
+		if err == nil {
+			return nil
+		}
+
+		if d.ErrorHandler != nil {
+			d.ErrorHandler(ctx, update, err)
+		} else {
+			d.bot.log(ctx, slog.LevelError, "failed to handle update", slog.Int("update_id", update.UpdateID), slog.String("error", err.Error()))
+		}
+
+		return err
+	})
+}
+
+// chainUpdateMiddleware wraps handler with middleware, the first one being
+// the outermost.
+func chainUpdateMiddleware(handler UpdateHandler, middleware []UpdateMiddleware) UpdateHandler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+
+	return handler
+}
+
+// isNewMessage is a filter matching updates with a new message.
+func isNewMessage(ctx context.Context, update *Update) bool {
+	return updateMessage(update, true) != nil
+}
@@ -0,0 +1,266 @@
+package tgbotapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// textMessage returns a message update with text, and a bot_command entity
+// if it starts with a slash.
+func textMessage(text string) Update {
+	message := &Message{Text: text, Chat: &Chat{ID: 1, Type: "private"}}
+	if strings.HasPrefix(text, "/") {
+		command, _, _ := strings.Cut(text, " ")
+		message.Entities = []MessageEntity{{Type: "bot_command", Length: len(command)}}
+	}
+
+	return Update{Message: message}
+}
+
+// recordUpdate returns a handler appending name to handled.
+func recordUpdate(handled *[]string, name string) UpdateHandler {
+	return func(ctx context.Context, update Update) error {
+		*handled = append(*handled, name)
+		return nil
+	}
+}
+
+func TestDispatcherRoutes(t *testing.T) {
+	bot := &BotAPI{Self: User{UserName: "test_bot"}}
+	d := NewDispatcher(bot)
+
+	var handled []string
+	d.Command("start", recordUpdate(&handled, "start"))
+	d.CallbackPrefix("vote:", recordUpdate(&handled, "vote"))
+	d.Regexp(regexp.MustCompile(`(?i)^hello`), recordUpdate(&handled, "hello"))
+	d.ContentType(ContentTypePhoto, recordUpdate(&handled, "photo"))
+	d.Handle(UpdateTypeEditedMessage, recordUpdate(&handled, "edited"))
+	d.Handle(UpdateTypeCallbackQuery, recordUpdate(&handled, "callback"))
+	d.Fallback(recordUpdate(&handled, "fallback"))
+
+	updates := []Update{
+		textMessage("/start"),
+		textMessage("/start@test_bot now"),
+		textMessage("/start@other_bot"),
+		textMessage("Hello there"),
+		{Message: &Message{Photo: []PhotoSize{{FileID: "photo"}}, Caption: "hello", Chat: &Chat{ID: 1}}},
+		{Message: &Message{Photo: []PhotoSize{{FileID: "photo"}}, Chat: &Chat{ID: 1}}},
+		{EditedMessage: &Message{Text: "/start", Chat: &Chat{ID: 1}}},
+		{CallbackQuery: &CallbackQuery{Data: "vote:1"}},
+		{CallbackQuery: &CallbackQuery{Data: "other"}},
+		{InlineQuery: &InlineQuery{Query: "query"}},
+	}
+
+	for _, update := range updates {
+		if err := d.Dispatch(update); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := "start,start,fallback,hello,hello,photo,edited,vote,callback,fallback"
+	if got := strings.Join(handled, ","); got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestDispatcherCommandWithoutUsername(t *testing.T) {
+	// The bot wasn't initialized, so its username is unknown.
+	d := NewDispatcher(&BotAPI{})
+
+	var handled []string
+	d.Command("start", recordUpdate(&handled, "start"))
+
+	for _, text := range []string{"/start", "/start@test_bot", "/start@other_bot"} {
+		if err := d.Dispatch(textMessage(text)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(handled) != 3 {
+		t.Errorf("expected commands addressed to any bot to be handled, got %v", handled)
+	}
+}
+
+func TestDispatcherMiddleware(t *testing.T) {
+	d := NewDispatcher(&BotAPI{})
+
+	var calls []string
+	trace := func(name string) UpdateMiddleware {
+		return func(next UpdateHandler) UpdateHandler {
+			return func(ctx context.Context, update Update) error {
+				calls = append(calls, name)
+				return next(ctx, update)
+			}
+		}
+	}
+	deny := func(next UpdateHandler) UpdateHandler {
+		return func(ctx context.Context, update Update) error {
+			return errors.New("denied")
+		}
+	}
+
+	d.Use(trace("outer"), trace("inner"))
+	d.Command("admin", recordUpdate(&calls, "admin"), deny)
+	d.Command("help", recordUpdate(&calls, "help"), trace("route"))
+
+	if err := d.Dispatch(textMessage("/admin")); err == nil || err.Error() != "denied" {
+		t.Errorf("expected the route middleware to deny the update, got %v", err)
+	}
+	if err := d.Dispatch(textMessage("/help")); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Dispatch(textMessage("unhandled")); err != nil {
+		t.Errorf("expected updates without a route to be ignored, got %v", err)
+	}
+
+	expected := "outer,inner,outer,inner,route,help,outer,inner"
+	if got := strings.Join(calls, ","); got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestDispatcherRun(t *testing.T) {
+	d := NewDispatcher(&BotAPI{})
+
+	var handled []string
+	d.Command("start", recordUpdate(&handled, "start"))
+	d.Command("fail", func(ctx context.Context, update Update) error {
+		return errors.New("failed")
+	})
+
+	var errs []error
+	d.ErrorHandler = func(ctx context.Context, update Update, err error) {
+		errs = append(errs, err)
+	}
+
+	updates := make(chan Update, 3)
+	updates <- textMessage("/start")
+	updates <- textMessage("/fail")
+	updates <- textMessage("/start")
+	close(updates)
+
+	d.Run(context.Background(), updates)
+
+	if len(handled) != 2 || len(errs) != 1 {
+		t.Errorf("expected 2 updates handled and 1 error, got %v and %v", handled, errs)
+	}
+}
+
+func TestDispatcherServeHTTP(t *testing.T) {
+	d := NewDispatcher(&BotAPI{})
+
+	var handled []string
+	d.Command("start", recordUpdate(&handled, "start"))
+
+	body := `{"update_id":1,"message":{"message_id":1,"text":"/start","chat":{"id":1},"entities":[{"type":"bot_command","offset":0,"length":6}]}}`
+	rec := httptest.NewRecorder()
+	d.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewBufferString(body)))
+
+	if rec.Code != http.StatusOK || len(handled) != 1 {
+		t.Errorf("expected the update to be handled, got status %d and %v", rec.Code, handled)
+	}
+
+	rec = httptest.NewRecorder()
+	d.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/webhook", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected a bad request, got status %d", rec.Code)
+	}
+}
+
+func TestDispatcherContext(t *testing.T) {
+	tracer := &recordingTracer{}
+	d := NewDispatcher(&BotAPI{Tracer: tracer})
+
+	var handled []context.Context
+	d.Fallback(func(ctx context.Context, update Update) error {
+		handled = append(handled, ctx)
+		if span, _ := ctx.Value(spanKey{}).(*recordedSpan); span == nil || span.name != "telegram.update" || span.ended {
+			t.Error("expected the handler's context to carry the update's span")
+		}
+		return nil
+	})
+
+	update := textMessage("hi")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	updates := make(chan Update, 1)
+	updates <- update
+	close(updates)
+	d.Run(ctx, updates)
+
+	if err := d.DispatchWithContext(ctx, update); err != nil {
+		t.Fatal(err)
+	}
+
+	body := `{"update_id":1,"message":{"message_id":1,"text":"hi","chat":{"id":1}}}`
+	req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewBufferString(body)).WithContext(ctx)
+	d.ServeHTTP(httptest.NewRecorder(), req)
+
+	cancel()
+	if len(handled) != 3 {
+		t.Fatalf("expected 3 updates handled, got %d", len(handled))
+	}
+	for _, ctx := range handled {
+		if ctx.Err() == nil {
+			t.Error("expected the handler's context to be canceled with the dispatching context")
+		}
+	}
+
+	if len(tracer.spans) != 3 {
+		t.Fatalf("expected a span for each update, got %d", len(tracer.spans))
+	}
+	for _, span := range tracer.spans {
+		if !span.ended {
+			t.Errorf("expected the update span to end once handled, got %+v", span)
+		}
+	}
+	if source := tracer.spans[2].attrs["telegram.source"]; source != UpdateSourceWebhook {
+		t.Errorf("expected the webhook update to be traced as such, got %q", source)
+	}
+}
+
+func TestUpdateType(t *testing.T) {
+	tests := []struct {
+		update   Update
+		expected string
+	}{
+		{Update{Message: &Message{}}, UpdateTypeMessage},
+		{Update{BusinessMessage: &Message{}}, UpdateTypeBusinessMessage},
+		{Update{MessageReaction: &MessageReactionUpdated{}}, UpdateTypeMessageReaction},
+		{Update{PurchasedPaidMedia: &PaidMediaPurchased{}}, UpdateTypePurchasedPaidMedia},
+		{Update{ChatMember: &ChatMemberUpdated{}}, UpdateTypeChatMember},
+		{Update{}, ""},
+	}
+
+	for _, test := range tests {
+		if got := test.update.Type(); got != test.expected {
+			t.Errorf("expected %q, got %q", test.expected, got)
+		}
+	}
+}
+
+func TestMessageContentType(t *testing.T) {
+	tests := []struct {
+		message  Message
+		expected string
+	}{
+		{Message{Text: "text"}, ContentTypeText},
+		{Message{Animation: &Animation{}, Document: &Document{}}, ContentTypeAnimation},
+		{Message{Document: &Document{}, Caption: "caption"}, ContentTypeDocument},
+		{Message{Venue: &Venue{}, Location: &Location{}}, ContentTypeVenue},
+		{Message{NewChatMembers: []User{{}}}, ContentTypeNewChatMembers},
+		{Message{GroupChatCreated: true}, ""},
+	}
+
+	for _, test := range tests {
+		if got := test.message.ContentType(); got != test.expected {
+			t.Errorf("expected %q, got %q", test.expected, got)
+		}
+	}
+}
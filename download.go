@@ -0,0 +1,225 @@
+package tgbotapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DefaultMaxDownloadSize is the largest file the Bot API lets bots download.
+// Local Bot API servers don't have this limit.
+const DefaultMaxDownloadSize = 20 << 20
+
+// maxDownloadResumes is how many times a download that was interrupted is
+// resumed before giving up.
+const maxDownloadResumes = 5
+
+var (
+	// ErrFileTooLarge is returned when downloading a file larger than the
+	// bot's MaxDownloadSize.
+	ErrFileTooLarge = errors.New("file is too large to download")
+	// ErrFileSizeMismatch is returned when a downloaded file doesn't have the
+	// size reported by getFile.
+	ErrFileSizeMismatch = errors.New("downloaded file size doesn't match")
+)
+
+// SetFileEndpoint changes the endpoint files are downloaded from, with
+// formatting for Sprintf like FileEndpoint. It is derived from the API
+// endpoint by default.
+func (bot *BotAPI) SetFileEndpoint(fileEndpoint string) {
+	bot.fileEndpoint = fileEndpoint
+}
+
+// getFileEndpoint returns the endpoint files are downloaded from. Unless set,
+// it is the API endpoint with /bot replaced by /file/bot.
+func (bot *BotAPI) getFileEndpoint() string {
+	if bot.fileEndpoint != "" {
+		return bot.fileEndpoint
+	}
+
+	if i := strings.LastIndex(bot.apiEndpoint, "/bot%s/"); i != -1 {
+		return bot.apiEndpoint[:i] + "/file/bot%s/%s"
+	}
+
+	return FileEndpoint
+}
+
+// DownloadFile downloads the file with the given ID to w, and returns its
+// information from getFile. The file is downloaded with the bot's HTTPClient,
+// without exposing the token: it is redacted from returned errors.
+//
+// Files larger than the bot's MaxDownloadSize fail with ErrFileTooLarge, and
+// files that don't have the size reported by getFile fail with
+// ErrFileSizeMismatch. Downloads interrupted by a network error are resumed
+// with a Range request.
+func (bot *BotAPI) DownloadFile(ctx context.Context, fileID string, w io.Writer) (File, error) {
+	return bot.download(ctx, fileID, w, 0)
+}
+
+// DownloadToPath downloads the file with the given ID to path, like
+// DownloadFile.
+//
+// The file is written to path with a .part suffix and renamed once complete.
+// If a .part file is left by a previous download, the download is resumed
+// from where it stopped.
+func (bot *BotAPI) DownloadToPath(ctx context.Context, fileID, path string) (File, error) {
+	partPath := path + ".part"
+
+	part, err := os.OpenFile(partPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return File{}, err
+	}
+
+	info, err := part.Stat()
+	if err != nil {
+		part.Close()
+		return File{}, err
+	}
+
+	file, err := bot.download(ctx, fileID, part, info.Size())
+	if closeErr := part.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		if errors.Is(err, ErrFileTooLarge) || errors.Is(err, ErrFileSizeMismatch) {
+			// The partial file can't be resumed.
+			_ = os.Remove(partPath)
+		}
+		return file, err
+	}
+
+	return file, os.Rename(partPath, path)
+}
+
+// download downloads a file to w, which already holds its first offset bytes.
+func (bot *BotAPI) download(ctx context.Context, fileID string, w io.Writer, offset int64) (File, error) {
+	file, err := bot.GetFileWithContext(ctx, FileConfig{FileID: fileID})
+	if err != nil {
+		return file, err
+	}
+
+	if file.FilePath == "" {
+		return file, fmt.Errorf("file %s has no path to download it from", fileID)
+	}
+	if limit := bot.maxDownloadSize(); limit > 0 && int64(file.FileSize) > limit {
+		return file, fmt.Errorf("%w: %d bytes", ErrFileTooLarge, file.FileSize)
+	}
+	if file.FileSize > 0 && offset > int64(file.FileSize) {
+		return file, fmt.Errorf("%w: already have %d bytes, expected %d", ErrFileSizeMismatch, offset, file.FileSize)
+	}
+
+	ctx, span := bot.startSpan(ctx, "telegram.download", slog.String("telegram.file_id", fileID))
+
+	written := offset
+	for resumes := 0; ; resumes++ {
+		if file.FileSize > 0 && written == int64(file.FileSize) {
+			break
+		}
+
+		n, resumable, err := bot.downloadRange(ctx, file, w, written)
+		written += n
+
+		if err == nil {
+			break
+		}
+		if !resumable || resumes == maxDownloadResumes || ctx.Err() != nil {
+			endSpan(span, err)
+			return file, err
+		}
+
+		bot.log(ctx, slog.LevelDebug, "resuming download", slog.String("file_id", fileID), slog.Int64("offset", written), slog.String("error", err.Error()))
+	}
+
+	if file.FileSize > 0 && written != int64(file.FileSize) {
+		err = fmt.Errorf("%w: got %d bytes, expected %d", ErrFileSizeMismatch, written, file.FileSize)
+	}
+
+	endSpan(span, err)
+
+	return file, err
+}
+
+// downloadRange downloads file to w from offset, and returns the number of
+// bytes written. Errors happening while reading the body are resumable.
+func (bot *BotAPI) downloadRange(ctx context.Context, file File, w io.Writer, offset int64) (int64, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(bot.getFileEndpoint(), bot.Token, file.FilePath), nil)
+	if err != nil {
+		return 0, false, bot.redactError(err)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", "bytes="+strconv.FormatInt(offset, 10)+"-")
+	}
+
+	resp, err := bot.Client.Do(req)
+	if err != nil {
+		return 0, false, bot.redactError(err)
+	}
+	defer resp.Body.Close()
+
+	body := io.Reader(resp.Body)
+
+	switch {
+	case offset > 0 && resp.StatusCode == http.StatusOK:
+		// The server ignored the range, skip what was already written.
+		if _, err := io.CopyN(io.Discard, body, offset); err != nil {
+			return 0, true, bot.redactError(err)
+		}
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && file.FileSize == 0:
+		// The file was already complete.
+		return 0, false, nil
+	case resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent:
+		return 0, false, &Error{Code: resp.StatusCode, Message: resp.Status}
+	}
+
+	limit := bot.maxDownloadSize()
+	if limit > 0 {
+		body = io.LimitReader(body, limit-offset+1)
+	}
+
+	dw := &downloadWriter{w: w}
+	n, err := io.Copy(dw, body)
+	if limit > 0 && offset+n > limit {
+		return n, false, fmt.Errorf("%w: more than %d bytes", ErrFileTooLarge, limit)
+	}
+	if err != nil {
+		// Only failures to read the body can be resumed.
+		return n, dw.err == nil, bot.redactError(err)
+	}
+
+	return n, false, nil
+}
+
+// maxDownloadSize returns the largest file the bot downloads, or 0 if there
+// is no limit.
+func (bot *BotAPI) maxDownloadSize() int64 {
+	switch {
+	case bot.MaxDownloadSize == 0:
+		return DefaultMaxDownloadSize
+	case bot.MaxDownloadSize < 0:
+		return 0
+	default:
+		return bot.MaxDownloadSize
+	}
+}
+
+// downloadWriter remembers the error returned by w, to tell write errors from
+// read errors.
+type downloadWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (dw *downloadWriter) Write(p []byte) (int, error) {
+	n, err := dw.w.Write(p)
+	if err != nil {
+		dw.err = err
+	}
+
+	return n, err
+}
@@ -0,0 +1,144 @@
+package tgbotapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newFileBot returns a bot downloading content as file "documents/file_1.txt",
+// and the number of times the file was requested. When interrupt is set, the
+// first download stops half way.
+func newFileBot(t *testing.T, content []byte, size int, interrupt bool) (*BotAPI, *atomic.Int32) {
+	t.Helper()
+
+	var downloads atomic.Int32
+	bot := newLocalBot(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/getFile") {
+			writeResult(w, `{"file_id":"id","file_unique_id":"unique","file_size":`+strconv.Itoa(size)+`,"file_path":"documents/file_1.txt"}`)
+			return
+		}
+
+		if r.URL.Path != "/file/bot123456:local-test-token/documents/file_1.txt" {
+			http.NotFound(w, r)
+			return
+		}
+
+		if downloads.Add(1) == 1 && interrupt {
+			w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+			_, _ = w.Write(content[:len(content)/2])
+			return
+		}
+
+		http.ServeContent(w, r, "file_1.txt", time.Time{}, bytes.NewReader(content))
+	})
+
+	return bot, &downloads
+}
+
+func TestDownloadFile(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 10000)
+	bot, downloads := newFileBot(t, content, len(content), true)
+
+	var buf bytes.Buffer
+	file, err := bot.DownloadFile(context.Background(), "id", &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.FilePath != "documents/file_1.txt" || !bytes.Equal(buf.Bytes(), content) {
+		t.Errorf("unexpected download of %+v, %d bytes", file, buf.Len())
+	}
+	if n := downloads.Load(); n != 2 {
+		t.Errorf("expected the interrupted download to be resumed, got %d requests", n)
+	}
+}
+
+func TestDownloadFileLimits(t *testing.T) {
+	content := []byte("some content")
+
+	bot, _ := newFileBot(t, content, len(content), false)
+	bot.MaxDownloadSize = 4
+	if _, err := bot.DownloadFile(context.Background(), "id", &bytes.Buffer{}); !errors.Is(err, ErrFileTooLarge) {
+		t.Errorf("expected ErrFileTooLarge for the reported size, got %v", err)
+	}
+
+	bot, downloads := newFileBot(t, content, 0, false)
+	bot.MaxDownloadSize = 4
+	if _, err := bot.DownloadFile(context.Background(), "id", &bytes.Buffer{}); !errors.Is(err, ErrFileTooLarge) {
+		t.Errorf("expected ErrFileTooLarge while downloading, got %v", err)
+	}
+	if downloads.Load() != 1 {
+		t.Error("expected the file to be downloaded when its size is unknown")
+	}
+
+	bot, _ = newFileBot(t, content, len(content)+1, false)
+	if _, err := bot.DownloadFile(context.Background(), "id", &bytes.Buffer{}); !errors.Is(err, ErrFileSizeMismatch) {
+		t.Errorf("expected ErrFileSizeMismatch, got %v", err)
+	}
+}
+
+func TestDownloadFileRedactsToken(t *testing.T) {
+	bot, _ := newFileBot(t, nil, 0, false)
+	bot.SetFileEndpoint("http://127.0.0.1:1/file/bot%s/%s")
+
+	_, err := bot.DownloadFile(context.Background(), "id", &bytes.Buffer{})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if strings.Contains(err.Error(), bot.Token) {
+		t.Errorf("expected the token to be redacted from %q", err)
+	}
+}
+
+func TestDownloadToPath(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 1000)
+	bot, downloads := newFileBot(t, content, len(content), false)
+
+	path := filepath.Join(t.TempDir(), "file.txt")
+	if err := os.WriteFile(path+".part", content[:4000], 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := bot.DownloadToPath(context.Background(), "id", path); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, content) {
+		t.Errorf("unexpected content of %d bytes", len(data))
+	}
+	if _, err := os.Stat(path + ".part"); !os.IsNotExist(err) {
+		t.Errorf("expected the partial file to be renamed, got %v", err)
+	}
+	if downloads.Load() != 1 {
+		t.Errorf("expected a single request, got %d", downloads.Load())
+	}
+}
+
+func TestFileEndpoint(t *testing.T) {
+	bot := &BotAPI{apiEndpoint: APIEndpoint}
+	if endpoint := bot.getFileEndpoint(); endpoint != FileEndpoint {
+		t.Errorf("expected %s, got %s", FileEndpoint, endpoint)
+	}
+
+	bot.SetAPIEndpoint("http://localhost:8081/bot%s/%s")
+	if endpoint := bot.getFileEndpoint(); endpoint != "http://localhost:8081/file/bot%s/%s" {
+		t.Errorf("unexpected endpoint %s", endpoint)
+	}
+
+	bot.SetFileEndpoint("http://files/%s/%s")
+	if endpoint := bot.getFileEndpoint(); endpoint != "http://files/%s/%s" {
+		t.Errorf("unexpected endpoint %s", endpoint)
+	}
+}
@@ -0,0 +1,92 @@
+package tgbotapi
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// DryRunFunc receives the requests a bot in dry-run mode doesn't send.
+type DryRunFunc func(ctx context.Context, req *APIRequest)
+
+// SetDryRun switches the bot to dry-run mode, or back to normal if fn is nil.
+//
+// In dry-run mode, requests to methods other than get* methods, which only
+// read data, are logged at the info level and passed to fn instead of being
+// sent to Telegram. They succeed with true as their result, so methods
+// returning a Message return an empty one. Requests to get* methods, such as
+// getUpdates, are still sent, so a bot can run against real updates in a
+// staging environment without answering them.
+//
+// The dry run happens after the middleware chain, so middleware sees requests
+// as usual. SetDryRun is not safe to call concurrently with requests.
+func (bot *BotAPI) SetDryRun(fn DryRunFunc) {
+	bot.dryRun = fn
+}
+
+// dryRunResult is the result of requests that aren't sent in dry-run mode.
+var dryRunResult = json.RawMessage("true")
+
+// performDryRun logs req and passes it to the bot's DryRunFunc instead of
+// sending it, unless it reads data. It reports whether req was handled.
+func (bot *BotAPI) performDryRun(ctx context.Context, req *APIRequest) (*APIResponse, bool) {
+	if bot.dryRun == nil || strings.HasPrefix(req.Endpoint, "get") {
+		return nil, false
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", req.Endpoint),
+		slog.Any("params", bot.redactParams(req.Params)),
+	}
+
+	if len(req.Files) > 0 {
+		names := make([]string, len(req.Files))
+		for i, file := range req.Files {
+			names[i] = file.Name
+		}
+		attrs = append(attrs, slog.Any("files", names))
+	}
+
+	bot.log(ctx, slog.LevelInfo, "dry run", attrs...)
+	bot.dryRun(ctx, req)
+
+	return &APIResponse{Ok: true, Result: dryRunResult}, true
+}
+
+// RequestLog collects the requests of a bot in dry-run mode, for use in
+// tests:
+//
+//	var requests tgbotapi.RequestLog
+//	bot.SetDryRun(requests.Record)
+//
+// It is safe for concurrent use.
+type RequestLog struct {
+	mu       sync.Mutex
+	requests []APIRequest
+}
+
+// Record adds req to the log. It is a DryRunFunc.
+func (l *RequestLog) Record(_ context.Context, req *APIRequest) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.requests = append(l.requests, *req)
+}
+
+// Requests returns the requests recorded so far, in order.
+func (l *RequestLog) Requests() []APIRequest {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return append([]APIRequest(nil), l.requests...)
+}
+
+// Reset forgets the requests recorded so far.
+func (l *RequestLog) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.requests = nil
+}
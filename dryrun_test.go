@@ -0,0 +1,104 @@
+package tgbotapi
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	bot := &BotAPI{}
+
+	req, err := bot.Render(NewMessage(42, "Hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Endpoint != "sendMessage" || req.Params["chat_id"] != "42" || req.Params["text"] != "Hello" || req.Files != nil {
+		t.Errorf("unexpected request %+v", req)
+	}
+
+	req, err = bot.Render(NewDocument(42, FileID("existing")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Params["document"] != "existing" || req.Files != nil {
+		t.Errorf("expected the file ID in params, got %+v", req)
+	}
+
+	req, err = bot.Render(NewDocument(42, FileBytes{Name: "notes.txt", Bytes: []byte("notes")}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(req.Files) != 1 || req.Files[0].Name != "document" {
+		t.Errorf("expected the document to be uploaded, got %+v", req.Files)
+	}
+
+	group := NewMediaGroup(42, []interface{}{
+		NewInputMediaPhoto(FileBytes{Name: "a.jpg", Bytes: []byte("a")}),
+		NewInputMediaPhoto(FileID("existing")),
+	})
+	req, err = bot.Render(group)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(req.Params["media"], `"attach://file-0"`) || !strings.Contains(req.Params["media"], `"existing"`) {
+		t.Errorf("expected attach:// in media, got %s", req.Params["media"])
+	}
+	if len(req.Files) != 1 || req.Files[0].Name != "file-0" {
+		t.Errorf("expected one file to upload, got %+v", req.Files)
+	}
+
+	if _, err := bot.Render(RawRequest{}); err == nil {
+		t.Error("expected the error of params to be returned")
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	var sent atomic.Int32
+	bot := newLocalBot(t, func(w http.ResponseWriter, r *http.Request) {
+		sent.Add(1)
+		writeResult(w, `{"id":1,"is_bot":true,"first_name":"Bot"}`)
+	})
+	bot.SetLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	var requests RequestLog
+	bot.SetDryRun(requests.Record)
+
+	msg, err := bot.Send(NewMessage(42, "Hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.MessageID != 0 {
+		t.Errorf("expected an empty message, got %+v", msg)
+	}
+
+	if _, err := bot.Send(NewDocument(42, FileBytes{Name: "notes.txt", Bytes: []byte("notes")})); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := bot.GetMe(); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := sent.Load(); n != 1 {
+		t.Errorf("expected only getMe to be sent, got %d requests", n)
+	}
+
+	logged := requests.Requests()
+	if len(logged) != 2 || logged[0].Endpoint != "sendMessage" || logged[0].Params["text"] != "Hello" || logged[1].Endpoint != "sendDocument" {
+		t.Errorf("unexpected requests %+v", logged)
+	}
+
+	requests.Reset()
+	bot.SetDryRun(nil)
+
+	if _, err := bot.Request(NewMessage(42, "Hello")); err != nil {
+		t.Fatal(err)
+	}
+	if n := sent.Load(); n != 2 || len(requests.Requests()) != 0 {
+		t.Errorf("expected the request to be sent after leaving dry-run mode, got %d requests", n)
+	}
+}
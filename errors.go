@@ -0,0 +1,149 @@
+package tgbotapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Errors reported by Telegram. An *Error returned by the bot matches these
+// with errors.Is, for example:
+//
+//	if errors.Is(err, tgbotapi.ErrBotBlocked) {
+//		// Stop sending messages to this user.
+//	}
+//
+// Errors matching a specific failure, such as ErrBotBlocked, also match the
+// error for their status code, such as ErrForbidden.
+var (
+	// ErrBadRequest matches all errors with status code 400.
+	ErrBadRequest = errors.New("bad request")
+	// ErrUnauthorized matches all errors with status code 401, usually
+	// caused by an invalid token.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden matches all errors with status code 403.
+	ErrForbidden = errors.New("forbidden")
+	// ErrNotFound matches all errors with status code 404.
+	ErrNotFound = errors.New("not found")
+	// ErrConflict matches all errors with status code 409, such as another
+	// instance calling getUpdates or an active webhook.
+	ErrConflict = errors.New("conflict")
+	// ErrTooManyRequests matches flood control errors, with status code 429.
+	// The time to wait is available in Error.RetryAfter.
+	ErrTooManyRequests = errors.New("too many requests")
+	// ErrServer matches all errors with a 5xx status code.
+	ErrServer = errors.New("telegram server error")
+
+	// ErrBotBlocked matches errors caused by the user blocking the bot.
+	ErrBotBlocked = errors.New("bot was blocked by the user")
+	// ErrBotKicked matches errors caused by the bot having been removed from
+	// a group, supergroup or channel.
+	ErrBotKicked = errors.New("bot was kicked from the chat")
+	// ErrUserDeactivated matches errors caused by the user's account having
+	// been deleted.
+	ErrUserDeactivated = errors.New("user is deactivated")
+	// ErrCantInitiateConversation matches errors caused by sending a message
+	// to a user who never started a conversation with the bot.
+	ErrCantInitiateConversation = errors.New("bot can't initiate conversation with a user")
+	// ErrChatNotFound matches errors caused by an unknown or inaccessible
+	// chat.
+	ErrChatNotFound = errors.New("chat not found")
+	// ErrMessageNotModified matches errors caused by editing a message
+	// without changing its content or markup.
+	ErrMessageNotModified = errors.New("message is not modified")
+	// ErrMessageToEditNotFound matches errors caused by editing a message
+	// that doesn't exist.
+	ErrMessageToEditNotFound = errors.New("message to edit not found")
+	// ErrMessageToDeleteNotFound matches errors caused by deleting a message
+	// that doesn't exist or was already deleted.
+	ErrMessageToDeleteNotFound = errors.New("message to delete not found")
+	// ErrWrongFileID matches errors caused by a file ID that is invalid or
+	// no longer usable.
+	ErrWrongFileID = errors.New("wrong file identifier")
+	// ErrChatMigrated matches errors caused by a group having been upgraded
+	// to a supergroup. The new chat ID is returned by Error.MigratedTo.
+	ErrChatMigrated = errors.New("group chat was upgraded to a supergroup chat")
+)
+
+// statusErrors maps status codes to the errors matching them.
+var statusErrors = map[int]error{
+	http.StatusBadRequest:      ErrBadRequest,
+	http.StatusUnauthorized:    ErrUnauthorized,
+	http.StatusForbidden:       ErrForbidden,
+	http.StatusNotFound:        ErrNotFound,
+	http.StatusConflict:        ErrConflict,
+	http.StatusTooManyRequests: ErrTooManyRequests,
+}
+
+// descriptionErrors are errors identified by the description Telegram
+// returns, along with the text identifying them.
+var descriptionErrors = map[error][]string{
+	ErrBotBlocked:               {"bot was blocked by the user"},
+	ErrBotKicked:                {"bot was kicked", "bot is not a member"},
+	ErrUserDeactivated:          {"user is deactivated"},
+	ErrCantInitiateConversation: {"bot can't initiate conversation with a user"},
+	ErrChatNotFound:             {"chat not found"},
+	ErrMessageNotModified:       {"message is not modified"},
+	ErrMessageToEditNotFound:    {"message to edit not found"},
+	ErrMessageToDeleteNotFound:  {"message to delete not found"},
+	ErrWrongFileID:              {"wrong file identifier", "wrong remote file identifier", "file reference expired"},
+	ErrChatMigrated:             {"group chat was upgraded to a supergroup chat"},
+}
+
+// Is reports whether the error matches target, one of the errors defined by
+// this package, such as ErrBotBlocked or ErrForbidden.
+func (e Error) Is(target error) bool {
+	if target == ErrServer {
+		return e.Code >= http.StatusInternalServerError
+	}
+
+	if target == ErrChatMigrated && e.MigrateToChatID != 0 {
+		return true
+	}
+
+	if statusErr, ok := statusErrors[e.Code]; ok && statusErr == target {
+		return true
+	}
+
+	description := strings.ToLower(e.Message)
+	for _, text := range descriptionErrors[target] {
+		if strings.Contains(description, text) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MigratedTo returns the ID of the supergroup a group was upgraded to, if the
+// error was caused by the group having been migrated.
+func (e Error) MigratedTo() (int64, bool) {
+	return e.MigrateToChatID, e.MigrateToChatID != 0
+}
+
+// IsRetryable returns true if the request that caused err could succeed when
+// made again: flood control errors, server errors and transport errors.
+// Other errors reported by Telegram and context errors are not retryable.
+//
+// Note that server and transport errors may happen after Telegram processed
+// the request, so retrying methods that send messages can cause duplicates.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Is(ErrTooManyRequests) || apiErr.RetryAfter > 0 || apiErr.Is(ErrServer)
+	}
+
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// IsForbidden returns true if err was caused by the bot not being allowed to
+// perform the request, such as the user blocking the bot or the bot having
+// been removed from a chat.
+func IsForbidden(err error) bool {
+	return errors.Is(err, ErrForbidden)
+}
@@ -0,0 +1,93 @@
+package tgbotapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestErrorIs(t *testing.T) {
+	blocked := &Error{Code: 403, Message: "Forbidden: bot was blocked by the user"}
+	notModified := &Error{Code: 400, Message: "Bad Request: message is not modified: specified new message content and reply markup are exactly the same"}
+	flood := &Error{Code: 429, Message: "Too Many Requests: retry after 5", ResponseParameters: ResponseParameters{RetryAfter: 5}}
+	migrated := &Error{Code: 400, Message: "Bad Request: group chat was upgraded to a supergroup chat", ResponseParameters: ResponseParameters{MigrateToChatID: -1001234}}
+
+	tests := []struct {
+		err    error
+		target error
+		want   bool
+	}{
+		{blocked, ErrBotBlocked, true},
+		{blocked, ErrForbidden, true},
+		{blocked, ErrChatNotFound, false},
+		{fmt.Errorf("sending: %w", blocked), ErrBotBlocked, true},
+		{notModified, ErrMessageNotModified, true},
+		{notModified, ErrBadRequest, true},
+		{notModified, ErrForbidden, false},
+		{&Error{Code: 400, Message: "Bad Request: chat not found"}, ErrChatNotFound, true},
+		{&Error{Code: 400, Message: "Bad Request: message to delete not found"}, ErrMessageToDeleteNotFound, true},
+		{flood, ErrTooManyRequests, true},
+		{migrated, ErrChatMigrated, true},
+		{&Error{Code: 502, Message: "Bad Gateway"}, ErrServer, true},
+		{errors.New("bot was blocked by the user"), ErrBotBlocked, false},
+	}
+
+	for _, test := range tests {
+		if got := errors.Is(test.err, test.target); got != test.want {
+			t.Errorf("errors.Is(%v, %v) = %v, want %v", test.err, test.target, got, test.want)
+		}
+	}
+}
+
+func TestErrorHelpers(t *testing.T) {
+	migrated := Error{Code: 400, ResponseParameters: ResponseParameters{MigrateToChatID: -1001234}}
+	if id, ok := migrated.MigratedTo(); !ok || id != -1001234 {
+		t.Errorf("MigratedTo() = %d, %v", id, ok)
+	}
+	if _, ok := (Error{Code: 400}).MigratedTo(); ok {
+		t.Error("expected MigratedTo to be false without a migration")
+	}
+
+	if !IsRetryable(&Error{Code: 429, ResponseParameters: ResponseParameters{RetryAfter: 1}}) {
+		t.Error("expected flood wait to be retryable")
+	}
+	if !IsRetryable(&Error{Code: 500}) {
+		t.Error("expected server error to be retryable")
+	}
+	if !IsRetryable(io.ErrUnexpectedEOF) {
+		t.Error("expected transport error to be retryable")
+	}
+	if IsRetryable(&Error{Code: 400, Message: "Bad Request: chat not found"}) {
+		t.Error("expected bad request not to be retryable")
+	}
+	if IsRetryable(context.Canceled) {
+		t.Error("expected context error not to be retryable")
+	}
+
+	if !IsForbidden(&Error{Code: 403, Message: "Forbidden: bot was kicked from the group chat"}) {
+		t.Error("expected 403 to be forbidden")
+	}
+}
+
+func TestUploadFilesErrorCode(t *testing.T) {
+	bot := newLocalBot(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = io.WriteString(w, `{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`)
+	})
+
+	_, err := bot.UploadFiles("sendDocument", Params{"chat_id": "1"}, []RequestFile{{
+		Name: "document",
+		Data: FileBytes{Name: "a.txt", Bytes: []byte("a")},
+	}})
+
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.Code != http.StatusBadRequest {
+		t.Fatalf("expected an Error with code 400, got %#v", err)
+	}
+	if !errors.Is(err, ErrChatNotFound) {
+		t.Errorf("expected ErrChatNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,496 @@
+package tgbotapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// FileIDStore stores the file IDs of uploaded files, by key.
+type FileIDStore interface {
+	// Get returns the file ID stored for key.
+	Get(key string) (fileID string, ok bool)
+	// Set stores the file ID for key.
+	Set(key, fileID string) error
+	// Delete forgets the file ID stored for key.
+	Delete(key string) error
+}
+
+// FileIDCache avoids uploading the same files again and again, by
+// remembering the file IDs Telegram returns for uploaded content.
+//
+// Files are identified by the SHA-256 hash of their content, along with the
+// kind of media they were sent as, such as photo or document. When a file
+// that was already uploaded is sent again, its file ID is sent instead. If
+// Telegram rejects the file ID, it is forgotten and the file is uploaded.
+//
+// Only files sent as media of a message, with send methods, sendMediaGroup
+// and editMessageMedia, are cached. Thumbnails are always uploaded.
+//
+// It is safe for concurrent use if its store is.
+type FileIDCache struct {
+	store FileIDStore
+	// bot logs failures of the store, if not nil.
+	bot *BotAPI
+}
+
+// NewFileIDCache creates a FileIDCache keeping file IDs in store.
+func NewFileIDCache(store FileIDStore) *FileIDCache {
+	return &FileIDCache{store: store}
+}
+
+// EnableFileIDCache makes the bot send the file IDs of files it already
+// uploaded instead of uploading them again, keeping file IDs in store. The
+// FileIDCache is returned.
+//
+// Files are read once to be hashed before being sent. Files that aren't a
+// ReplayableFileData, such as a FileReader, are kept in memory for the upload.
+//
+// It must be called before making requests.
+func (bot *BotAPI) EnableFileIDCache(store FileIDStore) *FileIDCache {
+	cache := NewFileIDCache(store)
+	cache.bot = bot
+	bot.Use(cache.Middleware())
+
+	return cache
+}
+
+// cachedMediaKinds are the kinds of media cached, which are the names of
+// the fields of single file methods and the types of InputMedia.
+var cachedMediaKinds = map[string]bool{
+	"photo":      true,
+	"document":   true,
+	"video":      true,
+	"audio":      true,
+	"animation":  true,
+	"voice":      true,
+	"video_note": true,
+	"sticker":    true,
+}
+
+// cachedFile is a file of a request that can be cached.
+type cachedFile struct {
+	// index is the index of the file in the request's files.
+	index int
+	// kind is the kind of media the file is sent as.
+	kind string
+	// item is the index of the file in the media param, or -1 if the file
+	// isn't sent as media.
+	item int
+	// key is the key of the file in the store.
+	key string
+}
+
+// Middleware returns a RequestMiddleware substituting file IDs for files
+// already uploaded.
+//
+// If Telegram rejects a file ID, it is deleted from the store and the request
+// is retried once with the file uploaded.
+func (c *FileIDCache) Middleware() RequestMiddleware {
+	return func(next RequestHandler) RequestHandler {
+		return func(ctx context.Context, req *APIRequest) (*APIResponse, error) {
+			if !hasFilesNeedingUpload(req.Files) {
+				return next(ctx, req)
+			}
+
+			files, err := c.cacheableFiles(req)
+			if err != nil {
+				return nil, err
+			}
+			if len(files) == 0 {
+				return next(ctx, req)
+			}
+
+			if cached, used := c.substitute(req, files); len(used) > 0 {
+				resp, err := next(ctx, cached)
+				if !errors.Is(err, ErrWrongFileID) {
+					if err == nil {
+						c.remember(ctx, req, files, resp)
+					}
+					return resp, err
+				}
+
+				for _, file := range used {
+					c.logError(ctx, c.store.Delete(file.key))
+				}
+			}
+
+			resp, err := next(ctx, req)
+			if err == nil {
+				c.remember(ctx, req, files, resp)
+			}
+
+			return resp, err
+		}
+	}
+}
+
+// cacheableFiles returns the files of req that can be cached, hashing their
+// content. Files that can only be read once are replaced by FileBytes in req,
+// which gets a copy of its files.
+func (c *FileIDCache) cacheableFiles(req *APIRequest) ([]cachedFile, error) {
+	if !strings.HasPrefix(req.Endpoint, "send") && req.Endpoint != "editMessageMedia" {
+		return nil, nil
+	}
+
+	media := mediaItems(req.Params["media"])
+
+	var files []cachedFile
+	copied := false
+
+	for i, file := range req.Files {
+		if !file.Data.NeedsUpload() {
+			continue
+		}
+
+		kind, item := file.Name, -1
+		if attached, ok := media["attach://"+file.Name]; ok {
+			kind, item = attached.kind, attached.index
+		}
+		if !cachedMediaKinds[kind] {
+			continue
+		}
+
+		if !copied {
+			req.Files = append([]RequestFile(nil), req.Files...)
+			copied = true
+		}
+
+		hash, data, err := hashFile(file.Data)
+		if err != nil {
+			return nil, err
+		}
+		if data != nil {
+			req.Files[i].Data = *data
+		}
+
+		files = append(files, cachedFile{index: i, kind: kind, item: item, key: hash + ":" + kind})
+	}
+
+	return files, nil
+}
+
+// substitute returns a copy of req with the files known to the store
+// replaced by their file ID, and the files replaced.
+func (c *FileIDCache) substitute(req *APIRequest, files []cachedFile) (*APIRequest, []cachedFile) {
+	var used []cachedFile
+	ids := make(map[int]string)
+
+	for _, file := range files {
+		if id, ok := c.store.Get(file.key); ok {
+			ids[file.index] = id
+			used = append(used, file)
+		}
+	}
+
+	if len(used) == 0 {
+		return req, nil
+	}
+
+	cached := &APIRequest{Endpoint: req.Endpoint, Params: make(Params, len(req.Params)), types: req.types}
+	for key, value := range req.Params {
+		cached.Params[key] = value
+	}
+
+	for i, file := range req.Files {
+		id, ok := ids[i]
+		switch {
+		case !ok:
+			cached.Files = append(cached.Files, file)
+		case cached.Params["media"] != "":
+			quoted, _ := json.Marshal(id)
+			cached.Params["media"] = strings.ReplaceAll(cached.Params["media"], `"attach://`+file.Name+`"`, string(quoted))
+		default:
+			cached.Params[file.Name] = id
+		}
+	}
+
+	// Files that don't need to be uploaded are sent as params when nothing
+	// else is uploaded.
+	if !hasFilesNeedingUpload(cached.Files) {
+		for _, file := range cached.Files {
+			cached.Params[file.Name] = file.Data.SendData()
+		}
+		cached.Files = nil
+	}
+
+	return cached, used
+}
+
+// remember stores the file IDs of files in the response to req.
+func (c *FileIDCache) remember(ctx context.Context, req *APIRequest, files []cachedFile, resp *APIResponse) {
+	if resp == nil {
+		return
+	}
+
+	var messages []Message
+	if req.Endpoint == "sendMediaGroup" {
+		if json.Unmarshal(resp.Result, &messages) != nil {
+			return
+		}
+	} else {
+		var message Message
+		if json.Unmarshal(resp.Result, &message) != nil {
+			return
+		}
+		messages = []Message{message}
+	}
+
+	for _, file := range files {
+		i := 0
+		if req.Endpoint == "sendMediaGroup" {
+			i = file.item
+		}
+		if i < 0 || i >= len(messages) {
+			continue
+		}
+
+		if id := messageFileID(&messages[i], file.kind); id != "" {
+			c.logError(ctx, c.store.Set(file.key, id))
+		}
+	}
+}
+
+// logError logs a failure of the store, which doesn't fail the request.
+func (c *FileIDCache) logError(ctx context.Context, err error) {
+	if err == nil || c.bot == nil {
+		return
+	}
+
+	c.bot.log(ctx, slog.LevelWarn, "file ID cache failed", slog.String("error", err.Error()))
+}
+
+// mediaItem is a file attached in a media param.
+type mediaItem struct {
+	kind  string
+	index int
+}
+
+// mediaItems returns the items of a media param, which is either a single
+// InputMedia or an array of them, by the value of their media field.
+func mediaItems(param string) map[string]mediaItem {
+	if param == "" {
+		return nil
+	}
+
+	type inputMedia struct {
+		Type  string `json:"type"`
+		Media string `json:"media"`
+	}
+
+	var items []inputMedia
+	if json.Unmarshal([]byte(param), &items) != nil {
+		var item inputMedia
+		if json.Unmarshal([]byte(param), &item) != nil {
+			return nil
+		}
+		items = []inputMedia{item}
+	}
+
+	media := make(map[string]mediaItem, len(items))
+	for i, item := range items {
+		media[item.Media] = mediaItem{kind: item.Type, index: i}
+	}
+
+	return media
+}
+
+// hashFile returns the hex encoded SHA-256 hash of the content of data. Data
+// that can't be read again is returned as FileBytes.
+func hashFile(data RequestFileData) (string, *FileBytes, error) {
+	h := sha256.New()
+
+	if data, ok := data.(FileBytes); ok {
+		h.Write(data.Bytes)
+		return hex.EncodeToString(h.Sum(nil)), nil, nil
+	}
+
+	name, reader, err := data.UploadData()
+	if err != nil {
+		return "", nil, err
+	}
+	if closer, ok := reader.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	if _, ok := data.(ReplayableFileData); ok {
+		if _, err := io.Copy(h, reader); err != nil {
+			return "", nil, err
+		}
+
+		return hex.EncodeToString(h.Sum(nil)), nil, nil
+	}
+
+	var buf bytes.Buffer
+	if _, err := io.Copy(io.MultiWriter(h, &buf), reader); err != nil {
+		return "", nil, err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), &FileBytes{Name: name, Bytes: buf.Bytes()}, nil
+}
+
+// messageFileID returns the ID of the file of the given kind in message.
+func messageFileID(message *Message, kind string) string {
+	switch {
+	case kind == "photo" && len(message.Photo) > 0:
+		return message.Photo[len(message.Photo)-1].FileID
+	case kind == "document" && message.Document != nil:
+		return message.Document.FileID
+	case kind == "video" && message.Video != nil:
+		return message.Video.FileID
+	case kind == "audio" && message.Audio != nil:
+		return message.Audio.FileID
+	case kind == "animation" && message.Animation != nil:
+		return message.Animation.FileID
+	case kind == "voice" && message.Voice != nil:
+		return message.Voice.FileID
+	case kind == "video_note" && message.VideoNote != nil:
+		return message.VideoNote.FileID
+	case kind == "sticker" && message.Sticker != nil:
+		return message.Sticker.FileID
+	default:
+		return ""
+	}
+}
+
+// MemoryFileIDStore is a FileIDStore keeping file IDs in memory.
+//
+// It is safe for concurrent use.
+type MemoryFileIDStore struct {
+	mu  sync.RWMutex
+	ids map[string]string
+}
+
+// NewMemoryFileIDStore creates an empty MemoryFileIDStore.
+func NewMemoryFileIDStore() *MemoryFileIDStore {
+	return &MemoryFileIDStore{ids: make(map[string]string)}
+}
+
+// Get implements FileIDStore.
+func (s *MemoryFileIDStore) Get(key string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	id, ok := s.ids[key]
+	return id, ok
+}
+
+// Set implements FileIDStore.
+func (s *MemoryFileIDStore) Set(key, fileID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ids[key] = fileID
+	return nil
+}
+
+// Delete implements FileIDStore.
+func (s *MemoryFileIDStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.ids, key)
+	return nil
+}
+
+// JSONFileIDStore is a FileIDStore keeping file IDs in a JSON file, so they
+// survive restarts. The file is rewritten on every change.
+//
+// It is safe for concurrent use, but not by several processes.
+type JSONFileIDStore struct {
+	path string
+
+	mu  sync.RWMutex
+	ids map[string]string
+}
+
+// NewJSONFileIDStore creates a JSONFileIDStore using the file at path, which
+// is created on the first change if it doesn't exist.
+func NewJSONFileIDStore(path string) (*JSONFileIDStore, error) {
+	s := &JSONFileIDStore{path: path, ids: make(map[string]string)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &s.ids); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Get implements FileIDStore.
+func (s *JSONFileIDStore) Get(key string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	id, ok := s.ids[key]
+	return id, ok
+}
+
+// Set implements FileIDStore.
+func (s *JSONFileIDStore) Set(key, fileID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ids[key] == fileID {
+		return nil
+	}
+
+	s.ids[key] = fileID
+	return s.save()
+}
+
+// Delete implements FileIDStore.
+func (s *JSONFileIDStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.ids[key]; !ok {
+		return nil
+	}
+
+	delete(s.ids, key)
+	return s.save()
+}
+
+// save writes the file IDs to a temporary file renamed over the store's
+// file, so it is never left half written. It must be called with the lock
+// held.
+func (s *JSONFileIDStore) save() error {
+	data, err := json.MarshalIndent(s.ids, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.path)
+}
@@ -0,0 +1,164 @@
+package tgbotapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fileServer answers sendDocument and sendMediaGroup, giving a new file ID to
+// every uploaded file and rejecting the file IDs in stale.
+type fileServer struct {
+	mu      sync.Mutex
+	uploads int
+	sent    []string
+	stale   map[string]bool
+}
+
+func (s *fileServer) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_ = r.ParseMultipartForm(1 << 20)
+
+	fileID := func(field, value string) string {
+		if r.MultipartForm != nil && len(r.MultipartForm.File[field]) > 0 {
+			s.uploads++
+			return fmt.Sprintf("uploaded-%d", s.uploads)
+		}
+		return value
+	}
+
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/sendDocument"):
+		id := fileID("document", r.FormValue("document"))
+		s.sent = append(s.sent, id)
+		if s.stale[id] {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = io.WriteString(w, `{"ok":false,"error_code":400,"description":"Bad Request: wrong file identifier/HTTP URL specified"}`)
+			return
+		}
+		writeResult(w, `{"message_id":1,"document":{"file_id":"`+id+`","file_unique_id":"u"}}`)
+	case strings.HasSuffix(r.URL.Path, "/sendMediaGroup"):
+		var media []struct {
+			Media string `json:"media"`
+		}
+		_ = json.Unmarshal([]byte(r.FormValue("media")), &media)
+
+		var messages []string
+		for _, item := range media {
+			id := item.Media
+			if name, ok := strings.CutPrefix(id, "attach://"); ok {
+				id = fileID(name, "")
+			}
+			s.sent = append(s.sent, id)
+			messages = append(messages, `{"message_id":1,"photo":[{"file_id":"thumb"},{"file_id":"`+id+`"}]}`)
+		}
+		writeResult(w, "["+strings.Join(messages, ",")+"]")
+	default:
+		writeResult(w, "true")
+	}
+}
+
+func TestFileIDCache(t *testing.T) {
+	server := &fileServer{stale: make(map[string]bool)}
+	bot := newLocalBot(t, server.handle)
+	store := NewMemoryFileIDStore()
+	bot.EnableFileIDCache(store)
+
+	logo := FileBytes{Name: "logo.png", Bytes: []byte("logo")}
+
+	for i := 0; i < 3; i++ {
+		msg, err := bot.Send(NewDocument(1, logo))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if msg.Document.FileID != "uploaded-1" {
+			t.Errorf("expected the first file ID, got %s", msg.Document.FileID)
+		}
+	}
+	if server.uploads != 1 {
+		t.Errorf("expected the file to be uploaded once, got %d uploads", server.uploads)
+	}
+
+	if _, err := bot.Send(NewDocument(1, FileReader{Name: "logo.png", Reader: strings.NewReader("logo")})); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bot.Send(NewDocument(1, FileBytes{Name: "other.png", Bytes: []byte("other")})); err != nil {
+		t.Fatal(err)
+	}
+	if server.uploads != 2 {
+		t.Errorf("expected only different content to be uploaded, got %d uploads", server.uploads)
+	}
+
+	server.stale["uploaded-1"] = true
+	msg, err := bot.Send(NewDocument(1, logo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Document.FileID != "uploaded-3" || server.uploads != 3 {
+		t.Errorf("expected the stale file to be uploaded again, got %s after %d uploads", msg.Document.FileID, server.uploads)
+	}
+	if _, err := bot.Send(NewDocument(1, logo)); err != nil || server.sent[len(server.sent)-1] != "uploaded-3" {
+		t.Errorf("expected the new file ID to be cached, got %v", server.sent)
+	}
+}
+
+func TestFileIDCacheMediaGroup(t *testing.T) {
+	server := &fileServer{}
+	bot := newLocalBot(t, server.handle)
+	bot.EnableFileIDCache(NewMemoryFileIDStore())
+
+	group := NewMediaGroup(1, []interface{}{
+		NewInputMediaPhoto(FileBytes{Name: "a.jpg", Bytes: []byte("a")}),
+		NewInputMediaPhoto(FileID("existing")),
+		NewInputMediaPhoto(FileBytes{Name: "b.jpg", Bytes: []byte("b")}),
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := bot.SendMediaGroup(group); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if server.uploads != 2 {
+		t.Errorf("expected the photos to be uploaded once, got %d uploads", server.uploads)
+	}
+	if got := strings.Join(server.sent[3:], ","); got != "uploaded-1,existing,uploaded-2" {
+		t.Errorf("expected the cached file IDs to be sent, got %s", got)
+	}
+}
+
+func TestJSONFileIDStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file_ids.json")
+
+	store, err := NewJSONFileIDStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Set("a", "id-a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Set("b", "id-b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete("a"); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err = NewJSONFileIDStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store.Get("a"); ok {
+		t.Error("expected a to be deleted")
+	}
+	if id, ok := store.Get("b"); !ok || id != "id-b" {
+		t.Errorf("expected b to be stored, got %q", id)
+	}
+}
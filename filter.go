@@ -0,0 +1,254 @@
+package tgbotapi
+
+import (
+	"context"
+	"regexp"
+	"strings"
+)
+
+// Filter reports whether an update should be handled. The context is the one
+// the update is dispatched with, so filters calling the Bot API stop when it is
+// canceled. Filters are combined
+// with And, Or and Not, and used to route updates with Dispatcher.Match:
+//
+//	d.Match(tgbotapi.And(
+//		tgbotapi.GroupChat(),
+//		tgbotapi.HasContentType(tgbotapi.ContentTypePhoto),
+//		tgbotapi.Not(tgbotapi.Forwarded()),
+//	), handler)
+//
+// Filters about messages look at the message of message, channel post and
+// business message updates, including edited ones, and don't match other
+// updates.
+type Filter func(ctx context.Context, update *Update) bool
+
+// And returns a filter matching updates matched by all the filters.
+func And(filters ...Filter) Filter {
+	return func(ctx context.Context, update *Update) bool {
+		for _, filter := range filters {
+			if !filter(ctx, update) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// Or returns a filter matching updates matched by any of the filters.
+func Or(filters ...Filter) Filter {
+	return func(ctx context.Context, update *Update) bool {
+		for _, filter := range filters {
+			if filter(ctx, update) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// Not returns a filter matching updates not matched by filter.
+func Not(filter Filter) Filter {
+	return func(ctx context.Context, update *Update) bool {
+		return !filter(ctx, update)
+	}
+}
+
+// IsUpdateType returns a filter matching updates of one of the given types,
+// the UpdateType constants.
+func IsUpdateType(updateTypes ...string) Filter {
+	return func(ctx context.Context, update *Update) bool {
+		updateType := update.Type()
+		for _, t := range updateTypes {
+			if t == updateType {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// PrivateChat returns a filter matching updates from private chats.
+func PrivateChat() Filter {
+	return func(ctx context.Context, update *Update) bool {
+		chat := update.FromChat()
+		return chat != nil && chat.IsPrivate()
+	}
+}
+
+// GroupChat returns a filter matching updates from groups and supergroups.
+func GroupChat() Filter {
+	return func(ctx context.Context, update *Update) bool {
+		chat := update.FromChat()
+		return chat != nil && (chat.IsGroup() || chat.IsSuperGroup())
+	}
+}
+
+// ChannelChat returns a filter matching updates from channels.
+func ChannelChat() Filter {
+	return func(ctx context.Context, update *Update) bool {
+		chat := update.FromChat()
+		return chat != nil && chat.IsChannel()
+	}
+}
+
+// HasContentType returns a filter matching messages with one of the given
+// content types, the ContentType constants.
+func HasContentType(contentTypes ...string) Filter {
+	return func(ctx context.Context, update *Update) bool {
+		message := updateMessage(update, false)
+		if message == nil {
+			return false
+		}
+
+		contentType := message.ContentType()
+		for _, t := range contentTypes {
+			if t == contentType {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// Forwarded returns a filter matching forwarded messages.
+func Forwarded() Filter {
+	return func(ctx context.Context, update *Update) bool {
+		message := updateMessage(update, false)
+		return message != nil && message.ForwardOrigin != nil
+	}
+}
+
+// ReplyToBot returns a filter matching messages replying to a message sent by
+// the bot.
+func ReplyToBot(bot *BotAPI) Filter {
+	return func(ctx context.Context, update *Update) bool {
+		message := updateMessage(update, false)
+		if message == nil || message.ReplyToMessage == nil || message.ReplyToMessage.From == nil {
+			return false
+		}
+
+		return message.ReplyToMessage.From.ID == bot.Self.ID
+	}
+}
+
+// FromAdmin returns a filter matching messages sent by a creator or an
+// administrator of the chat, including anonymous administrators sending
+// messages on behalf of the chat.
+//
+// The filter checks the sender with getChatMember for every message it is
+// given, so it should come after cheaper filters in And. Messages whose
+// sender can't be checked, including because ctx is canceled, aren't matched.
+func FromAdmin(bot *BotAPI) Filter {
+	return func(ctx context.Context, update *Update) bool {
+		message := updateMessage(update, false)
+		if message == nil || message.Chat == nil || message.Chat.IsPrivate() {
+			return false
+		}
+
+		if message.SenderChat != nil {
+			return message.SenderChat.ID == message.Chat.ID
+		}
+		if message.From == nil {
+			return false
+		}
+
+		member, err := bot.GetChatMemberWithContext(ctx, GetChatMemberConfig{
+			ChatConfigWithUser: ChatConfigWithUser{ChatID: message.Chat.ID, UserID: message.From.ID},
+		})
+		if err != nil {
+			return false
+		}
+
+		return member.IsCreator() || member.IsAdministrator()
+	}
+}
+
+// TextMatches returns a filter matching messages whose text or caption
+// matches re.
+func TextMatches(re *regexp.Regexp) Filter {
+	return func(ctx context.Context, update *Update) bool {
+		message := updateMessage(update, false)
+		if message == nil {
+			return false
+		}
+
+		if message.Text != "" {
+			return re.MatchString(message.Text)
+		}
+		return message.Caption != "" && re.MatchString(message.Caption)
+	}
+}
+
+// HasEntity returns a filter matching messages whose text or caption has an
+// entity of the given type, such as "mention", "hashtag" or "url".
+func HasEntity(entityType string) Filter {
+	return func(ctx context.Context, update *Update) bool {
+		message := updateMessage(update, false)
+		if message == nil {
+			return false
+		}
+
+		for _, entities := range [][]MessageEntity{message.Entities, message.CaptionEntities} {
+			for _, entity := range entities {
+				if entity.Type == entityType {
+					return true
+				}
+			}
+		}
+
+		return false
+	}
+}
+
+// LanguageCode returns a filter matching updates sent by users with one of
+// the given language codes. A language without a region, such as "en",
+// matches all its regions, such as "en-US".
+func LanguageCode(codes ...string) Filter {
+	return func(ctx context.Context, update *Update) bool {
+		user := update.SentFrom()
+		if user == nil || user.LanguageCode == "" {
+			return false
+		}
+
+		for _, code := range codes {
+			if strings.EqualFold(user.LanguageCode, code) || hasPrefixFold(user.LanguageCode, code+"-") {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// hasPrefixFold is strings.HasPrefix ignoring case.
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
+
+// updateMessage returns the message of a message, channel post or business
+// message update, or nil for other updates. Edited messages are only returned
+// if newOnly is false.
+func updateMessage(update *Update, newOnly bool) *Message {
+	switch {
+	case update.Message != nil:
+		return update.Message
+	case update.ChannelPost != nil:
+		return update.ChannelPost
+	case update.BusinessMessage != nil:
+		return update.BusinessMessage
+	case newOnly:
+		return nil
+	case update.EditedMessage != nil:
+		return update.EditedMessage
+	case update.EditedChannelPost != nil:
+		return update.EditedChannelPost
+	case update.EditedBusinessMessage != nil:
+		return update.EditedBusinessMessage
+	default:
+		return nil
+	}
+}
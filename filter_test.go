@@ -0,0 +1,120 @@
+package tgbotapi
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"testing"
+)
+
+func TestFilterCombinators(t *testing.T) {
+	yes := Filter(func(ctx context.Context, update *Update) bool { return true })
+	no := Filter(func(ctx context.Context, update *Update) bool { return false })
+	update := &Update{}
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		expected bool
+	}{
+		{"And", And(yes, yes), true},
+		{"And with a false filter", And(yes, no), false},
+		{"empty And", And(), true},
+		{"Or", Or(no, yes), true},
+		{"Or without a true filter", Or(no, no), false},
+		{"empty Or", Or(), false},
+		{"Not", Not(no), true},
+		{"nested", And(Or(no, yes), Not(And(yes, no))), true},
+	}
+
+	for _, test := range tests {
+		if got := test.filter(context.Background(), update); got != test.expected {
+			t.Errorf("%s: expected %t, got %t", test.name, test.expected, got)
+		}
+	}
+}
+
+func TestFilters(t *testing.T) {
+	bot := &BotAPI{Self: User{ID: 100}}
+	group := &Chat{ID: -1, Type: "supergroup"}
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		update   Update
+		expected bool
+	}{
+		{"private chat", PrivateChat(), Update{Message: &Message{Chat: &Chat{Type: "private"}}}, true},
+		{"private chat in a group", PrivateChat(), Update{Message: &Message{Chat: group}}, false},
+		{"group chat", GroupChat(), Update{CallbackQuery: &CallbackQuery{Message: &Message{Chat: group}}}, true},
+		{"group chat without a chat", GroupChat(), Update{InlineQuery: &InlineQuery{}}, false},
+		{"channel chat", ChannelChat(), Update{ChannelPost: &Message{Chat: &Chat{Type: "channel"}}}, true},
+		{"update type", IsUpdateType(UpdateTypeEditedMessage, UpdateTypeChannelPost), Update{EditedMessage: &Message{}}, true},
+		{"other update type", IsUpdateType(UpdateTypeMessage), Update{EditedMessage: &Message{}}, false},
+		{"photo", HasContentType(ContentTypePhoto), Update{EditedMessage: &Message{Photo: []PhotoSize{{}}}}, true},
+		{"voice or document", HasContentType(ContentTypeVoice, ContentTypeDocument), Update{Message: &Message{Document: &Document{}}}, true},
+		{"photo without a message", HasContentType(ContentTypePhoto), Update{CallbackQuery: &CallbackQuery{}}, false},
+		{"forwarded", Forwarded(), Update{Message: &Message{ForwardOrigin: &MessageOrigin{Type: "user"}}}, true},
+		{"not forwarded", Forwarded(), Update{Message: &Message{}}, false},
+		{"reply to bot", ReplyToBot(bot), Update{Message: &Message{ReplyToMessage: &Message{From: &User{ID: 100}}}}, true},
+		{"reply to user", ReplyToBot(bot), Update{Message: &Message{ReplyToMessage: &Message{From: &User{ID: 1}}}}, false},
+		{"text matches", TextMatches(regexp.MustCompile(`^\d+$`)), Update{Message: &Message{Text: "42"}}, true},
+		{"caption matches", TextMatches(regexp.MustCompile(`cat`)), Update{Message: &Message{Caption: "a cat"}}, true},
+		{"text doesn't match", TextMatches(regexp.MustCompile(`^\d+$`)), Update{Message: &Message{Text: "forty two"}}, false},
+		{"entity", HasEntity("mention"), Update{Message: &Message{Entities: []MessageEntity{{Type: "bold"}, {Type: "mention"}}}}, true},
+		{"caption entity", HasEntity("url"), Update{Message: &Message{CaptionEntities: []MessageEntity{{Type: "url"}}}}, true},
+		{"missing entity", HasEntity("hashtag"), Update{Message: &Message{Entities: []MessageEntity{{Type: "mention"}}}}, false},
+		{"language", LanguageCode("en"), Update{Message: &Message{From: &User{LanguageCode: "en-US"}}}, true},
+		{"language with region", LanguageCode("pt-BR"), Update{CallbackQuery: &CallbackQuery{From: &User{LanguageCode: "pt-br"}}}, true},
+		{"other language", LanguageCode("en"), Update{Message: &Message{From: &User{LanguageCode: "eo"}}}, false},
+		{"unknown language", LanguageCode("en"), Update{Message: &Message{From: &User{}}}, false},
+	}
+
+	for _, test := range tests {
+		if got := test.filter(context.Background(), &test.update); got != test.expected {
+			t.Errorf("%s: expected %t, got %t", test.name, test.expected, got)
+		}
+	}
+}
+
+func TestFromAdmin(t *testing.T) {
+	bot := newLocalBot(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.FormValue("user_id") {
+		case "1":
+			writeResult(w, `{"status":"administrator","user":{"id":1}}`)
+		case "2":
+			writeResult(w, `{"status":"member","user":{"id":2}}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"ok":false,"error_code":400,"description":"Bad Request: user not found"}`))
+		}
+	})
+
+	group := &Chat{ID: -1, Type: "supergroup"}
+	filter := FromAdmin(bot)
+
+	tests := []struct {
+		name     string
+		message  *Message
+		expected bool
+	}{
+		{"administrator", &Message{Chat: group, From: &User{ID: 1}}, true},
+		{"member", &Message{Chat: group, From: &User{ID: 2}}, false},
+		{"unknown user", &Message{Chat: group, From: &User{ID: 3}}, false},
+		{"anonymous administrator", &Message{Chat: group, SenderChat: group}, true},
+		{"other chat", &Message{Chat: group, SenderChat: &Chat{ID: -2}}, false},
+		{"private chat", &Message{Chat: &Chat{ID: 1, Type: "private"}, From: &User{ID: 1}}, false},
+	}
+
+	for _, test := range tests {
+		if got := filter(context.Background(), &Update{Message: test.message}); got != test.expected {
+			t.Errorf("%s: expected %t, got %t", test.name, test.expected, got)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if filter(ctx, &Update{Message: &Message{Chat: group, From: &User{ID: 1}}}) {
+		t.Error("expected an administrator not to be matched with a canceled context")
+	}
+}
@@ -1,5 +1,7 @@
 package tgbotapi
 
+import "encoding/json"
+
 type Gift struct {
 	// Unique identifier of the gift
 	Id string `json:"id"`
@@ -155,3 +157,103 @@ type GiftInfo struct {
 	// True, if the sender and gift text are shown only to the gift receiver; otherwise, everyone will be able to see them
 	IsPrivate bool `json:"is_private,omitempty"`
 }
+
+// Gifts represents a list of gifts.
+type Gifts struct {
+	// The list of gifts
+	Gifts []Gift `json:"gifts"`
+}
+
+// OwnedGift describes a gift received and owned by a user or a chat.
+//
+// Type is "regular" for regular gifts, which are stored in Gift, or "unique"
+// for unique gifts, which are stored in UniqueGift.
+type OwnedGift struct {
+	// Type of the gift, "regular" or "unique"
+	Type string `json:"type"`
+	// Information about a regular gift
+	Gift *Gift `json:"-"`
+	// Information about a unique gift
+	UniqueGift *UniqueGift `json:"-"`
+	// Optional. Unique identifier of the gift for the bot; for gifts received on behalf of business accounts only
+	OwnedGiftID string `json:"owned_gift_id,omitempty"`
+	// Optional. Sender of the gift if it is a known user
+	SenderUser *User `json:"sender_user,omitempty"`
+	// Date the gift was sent in Unix time
+	SendDate int `json:"send_date"`
+	// Optional. Text of the message that was added to the gift
+	Text string `json:"text,omitempty"`
+	// Optional. Special entities that appear in the text
+	Entities []MessageEntity `json:"entities,omitempty"`
+	// Optional. True, if the sender and gift text are shown only to the gift receiver
+	IsPrivate bool `json:"is_private,omitempty"`
+	// Optional. True, if the gift is displayed on the account's profile page
+	IsSaved bool `json:"is_saved,omitempty"`
+	// Optional. True, if the gift can be upgraded to a unique gift
+	CanBeUpgraded bool `json:"can_be_upgraded,omitempty"`
+	// Optional. True, if the gift was refunded and isn't available anymore
+	WasRefunded bool `json:"was_refunded,omitempty"`
+	// Optional. Number of Telegram Stars that can be claimed by converting the gift
+	ConvertStarCount int `json:"convert_star_count,omitempty"`
+	// Optional. Number of Telegram Stars that were paid by the sender for the ability to upgrade the gift
+	PrepaidUpgradeStarCount int `json:"prepaid_upgrade_star_count,omitempty"`
+	// Optional. True, if the unique gift can be transferred to another owner
+	CanBeTransferred bool `json:"can_be_transferred,omitempty"`
+	// Optional. Number of Telegram Stars that must be paid to transfer the unique gift
+	TransferStarCount int `json:"transfer_star_count,omitempty"`
+	// Optional. Point in time (Unix timestamp) when the unique gift can be transferred
+	NextTransferDate int `json:"next_transfer_date,omitempty"`
+}
+
+// ownedGift has the fields of OwnedGift without its JSON methods.
+type ownedGift OwnedGift
+
+// UnmarshalJSON decodes the gift field of an OwnedGift according to its type.
+func (g *OwnedGift) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		ownedGift
+		Gift json.RawMessage `json:"gift"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*g = OwnedGift(raw.ownedGift)
+
+	if len(raw.Gift) == 0 {
+		return nil
+	}
+
+	if g.Type == "unique" {
+		g.UniqueGift = &UniqueGift{}
+		return json.Unmarshal(raw.Gift, g.UniqueGift)
+	}
+
+	g.Gift = &Gift{}
+	return json.Unmarshal(raw.Gift, g.Gift)
+}
+
+// MarshalJSON encodes the regular or unique gift as the gift field.
+func (g OwnedGift) MarshalJSON() ([]byte, error) {
+	var gift interface{}
+	if g.UniqueGift != nil {
+		gift = g.UniqueGift
+	} else if g.Gift != nil {
+		gift = g.Gift
+	}
+
+	return json.Marshal(struct {
+		ownedGift
+		Gift interface{} `json:"gift,omitempty"`
+	}{ownedGift(g), gift})
+}
+
+// OwnedGifts contains the list of gifts received and owned by a user or a chat.
+type OwnedGifts struct {
+	// The total number of gifts owned by the user or the chat
+	TotalCount int `json:"total_count"`
+	// The list of gifts
+	Gifts []OwnedGift `json:"gifts"`
+	// Optional. Offset for the next request. If empty, then there are no more results
+	NextOffset string `json:"next_offset,omitempty"`
+}
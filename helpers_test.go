@@ -2,6 +2,7 @@ package tgbotapi
 
 import (
 	"testing"
+	"testing/fstest"
 )
 
 func TestNewWebhook(t *testing.T) {
@@ -235,6 +236,25 @@ func TestNewDiceWithEmoji(t *testing.T) {
 	}
 }
 
+func TestNewPhotoParams(t *testing.T) {
+	photos := []PhotoConfig{
+		NewPhoto(42, FileID("id")),
+		NewPhoto(42, FileBytes{Name: "photo.jpg", Bytes: []byte("photo")}),
+		NewPhotoToChannel("@channel", FileURL("https://example.com/photo.jpg")),
+		NewPhoto(42, FileFS{FS: fstest.MapFS{"photo.jpg": {Data: []byte("photo")}}, Path: "photo.jpg"}),
+	}
+
+	for _, photo := range photos {
+		if _, err := photo.params(); err != nil {
+			t.Errorf("expected %T photo to be valid, got %v", photo.File, err)
+		}
+	}
+
+	if _, err := NewPhoto(0, FileID("id")).params(); err == nil {
+		t.Error("expected a photo without a chat to be invalid")
+	}
+}
+
 func TestValidateWebAppData(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		token := "5473903189:AAFnHnISQMP5UQQ5MEaoEWvxeiwNgz2CN2U"
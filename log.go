@@ -1,9 +1,15 @@
 package tgbotapi
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	stdlog "log"
+	"log/slog"
+	"net/url"
 	"os"
+	"strings"
+	"time"
 )
 
 // BotLogger is an interface that represents the required methods to log data.
@@ -18,6 +24,9 @@ type BotLogger interface {
 var log BotLogger = stdlog.New(os.Stderr, "", stdlog.LstdFlags)
 
 // SetLogger specifies the logger that the package should use.
+//
+// It is used by bots that weren't given a *slog.Logger with WithLogger or
+// BotAPI.SetLogger, and is shared by all of them.
 func SetLogger(logger BotLogger) error {
 	if logger == nil {
 		return errors.New("logger is nil")
@@ -25,3 +34,164 @@ func SetLogger(logger BotLogger) error {
 	log = logger
 	return nil
 }
+
+// SetLogger sets the logger used by the bot. Debug messages, such as the
+// params and result of every request, are logged when the logger has the
+// debug level enabled or when Debug is true.
+//
+// Attributes logged include the method, chat_id, latency and error_code of
+// requests. The bot's token is redacted from all messages.
+func (bot *BotAPI) SetLogger(logger *slog.Logger) {
+	bot.logger = logger
+}
+
+// redacted replaces the bot's token in logs and errors.
+const redacted = "<redacted>"
+
+// redact removes the bot's token from s.
+func (bot *BotAPI) redact(s string) string {
+	if bot.Token == "" {
+		return s
+	}
+
+	return strings.ReplaceAll(s, bot.Token, redacted)
+}
+
+// redactParams returns a copy of params with the bot's token removed from
+// their values.
+func (bot *BotAPI) redactParams(params Params) Params {
+	redactedParams := make(Params, len(params))
+	for key, value := range params {
+		redactedParams[key] = bot.redact(value)
+	}
+
+	return redactedParams
+}
+
+// redactError removes the bot's token from err, which usually contains it
+// when it is a *url.Error returned by the HTTP client. A *url.Error is
+// returned as a copy with a redacted URL, so it can still be used with
+// errors.As.
+func (bot *BotAPI) redactError(err error) error {
+	if err == nil || bot.Token == "" || !strings.Contains(err.Error(), bot.Token) {
+		return err
+	}
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) && err == error(urlErr) && !strings.Contains(urlErr.Err.Error(), bot.Token) {
+		return &url.Error{Op: urlErr.Op, URL: bot.redact(urlErr.URL), Err: urlErr.Err}
+	}
+
+	return &redactedError{err: err, msg: bot.redact(err.Error())}
+}
+
+// redactedError is an error whose message had the bot's token removed.
+type redactedError struct {
+	err error
+	msg string
+}
+
+func (e *redactedError) Error() string {
+	return e.msg
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// debugEnabled returns true if debug messages should be logged.
+func (bot *BotAPI) debugEnabled(ctx context.Context) bool {
+	return bot.Debug || bot.getLogger().Enabled(ctx, slog.LevelDebug)
+}
+
+// log logs msg with attrs at level. Debug messages are also logged when
+// Debug is true, even if the logger doesn't have the debug level enabled.
+// String values have the bot's token redacted.
+func (bot *BotAPI) log(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	logger := bot.getLogger()
+	if !logger.Enabled(ctx, level) && !(level == slog.LevelDebug && bot.Debug) {
+		return
+	}
+
+	for i, attr := range attrs {
+		if attr.Value.Kind() == slog.KindString {
+			attrs[i].Value = slog.StringValue(bot.redact(attr.Value.String()))
+		}
+	}
+
+	record := slog.NewRecord(time.Now(), level, msg, 0)
+	record.AddAttrs(attrs...)
+
+	_ = logger.Handler().Handle(ctx, record)
+}
+
+// getLogger returns the bot's logger, or a logger writing to the package
+// logger if it doesn't have one.
+func (bot *BotAPI) getLogger() *slog.Logger {
+	if bot.logger != nil {
+		return bot.logger
+	}
+
+	return packageLogger
+}
+
+// packageLogger is used by bots without a logger. It writes to the logger set
+// with SetLogger.
+var packageLogger = slog.New(&botLoggerHandler{})
+
+// botLoggerHandler is a slog.Handler writing records to the package logger as
+// text lines.
+type botLoggerHandler struct {
+	attrs  []slog.Attr
+	prefix string
+}
+
+func (h *botLoggerHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= slog.LevelInfo
+}
+
+func (h *botLoggerHandler) Handle(_ context.Context, record slog.Record) error {
+	var b strings.Builder
+
+	if record.Level != slog.LevelInfo {
+		b.WriteString(record.Level.String())
+		b.WriteByte(' ')
+	}
+	b.WriteString(record.Message)
+
+	write := func(prefix string, attr slog.Attr) {
+		fmt.Fprintf(&b, " %s%s=%v", prefix, attr.Key, attr.Value)
+	}
+
+	for _, attr := range h.attrs {
+		write("", attr)
+	}
+
+	record.Attrs(func(attr slog.Attr) bool {
+		write(h.prefix, attr)
+		return true
+	})
+
+	log.Println(b.String())
+
+	return nil
+}
+
+func (h *botLoggerHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handler := *h
+	handler.attrs = make([]slog.Attr, 0, len(h.attrs)+len(attrs))
+	handler.attrs = append(handler.attrs, h.attrs...)
+	for _, attr := range attrs {
+		attr.Key = h.prefix + attr.Key
+		handler.attrs = append(handler.attrs, attr)
+	}
+
+	return &handler
+}
+
+func (h *botLoggerHandler) WithGroup(name string) slog.Handler {
+	handler := *h
+	handler.prefix = h.prefix + name + "."
+
+	return &handler
+}
@@ -0,0 +1,125 @@
+package tgbotapi
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestRedactTransportError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	bot, err := New(TestToken, WithAPIEndpoint(srv.URL+"/bot%s/%s"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = bot.Send(NewMessage(1, "text"))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if strings.Contains(err.Error(), TestToken) {
+		t.Errorf("expected the token to be redacted from %q", err)
+	}
+
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) || !strings.Contains(urlErr.URL, "/bot"+redacted+"/sendMessage") {
+		t.Errorf("expected a redacted *url.Error, got %#v", err)
+	}
+}
+
+func TestRedactedError(t *testing.T) {
+	bot := &BotAPI{Token: TestToken}
+
+	inner := errors.New("boom")
+	err := bot.redactError(fmt.Errorf("calling %s: %w", TestToken, inner))
+
+	if strings.Contains(err.Error(), TestToken) || !errors.Is(err, inner) {
+		t.Errorf("unexpected redacted error %v", err)
+	}
+
+	if err := bot.redactError(inner); err != inner {
+		t.Errorf("expected errors without the token to be returned as is, got %v", err)
+	}
+}
+
+func TestRequestLogging(t *testing.T) {
+	bot := newLocalBot(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/setWebhook") {
+			writeResult(w, "true")
+			return
+		}
+
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"ok":false,"error_code":403,"description":"Forbidden: bot was blocked by the user"}`))
+	})
+
+	var logs bytes.Buffer
+	bot.SetLogger(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	webhook, err := NewWebhook("https://example.com/" + bot.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bot.Request(webhook); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bot.Send(NewMessage(42, "text")); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	out := logs.String()
+	if strings.Contains(out, bot.Token) {
+		t.Errorf("expected the token to be redacted from logs:\n%s", out)
+	}
+
+	for _, expected := range []string{"method=setWebhook", "method=sendMessage", "chat_id=42", "latency=", "error_code=403", redacted} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected logs to contain %q:\n%s", expected, out)
+		}
+	}
+}
+
+func TestPackageLoggerDebug(t *testing.T) {
+	var lines []string
+	defer SetLogger(log)
+	SetLogger(funcLogger(func(s string) { lines = append(lines, s) }))
+
+	bot := newLocalBot(t, func(w http.ResponseWriter, r *http.Request) {
+		writeResult(w, "true")
+	})
+
+	if _, err := bot.Request(NewChatAction(1, ChatTyping)); err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 0 {
+		t.Errorf("expected nothing to be logged without Debug, got %v", lines)
+	}
+
+	bot.Debug = true
+	if _, err := bot.Request(NewChatAction(1, ChatTyping)); err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 1 || !strings.HasPrefix(lines[0], "DEBUG request method=sendChatAction chat_id=1") {
+		t.Errorf("unexpected logs %q", lines)
+	}
+}
+
+// funcLogger is a BotLogger calling a function with every line.
+type funcLogger func(string)
+
+func (f funcLogger) Println(v ...interface{}) {
+	f(strings.TrimSuffix(fmt.Sprintln(v...), "\n"))
+}
+
+func (f funcLogger) Printf(format string, v ...interface{}) {
+	f(fmt.Sprintf(format, v...))
+}
@@ -0,0 +1,308 @@
+package tgbotapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Outcomes of requests reported to Metrics. Requests failing with an error
+// reported by Telegram have its error code as outcome, such as "403" or "429".
+const (
+	// OutcomeOK is the outcome of successful requests.
+	OutcomeOK = "ok"
+	// OutcomeCanceled is the outcome of requests whose context was canceled
+	// or timed out.
+	OutcomeCanceled = "canceled"
+	// OutcomeError is the outcome of requests that failed without a response
+	// from Telegram, such as network errors.
+	OutcomeError = "error"
+)
+
+// Sources of updates reported to Metrics.
+const (
+	UpdateSourcePolling = "polling"
+	UpdateSourceWebhook = "webhook"
+)
+
+// Metrics receives measurements about the requests made by a bot and the
+// updates it receives. Its methods may be called concurrently.
+type Metrics interface {
+	// ObserveRequest records a request to method, which ended with outcome
+	// after latency. Every attempt of retried requests is recorded.
+	ObserveRequest(method, outcome string, latency time.Duration)
+	// ObserveUploadBytes records the size of the body of a request uploading
+	// files.
+	ObserveUploadBytes(method string, n int64)
+	// ObserveRetryAfter records the time Telegram asked to wait before
+	// making more requests, after a request to method hit flood control.
+	ObserveRetryAfter(method string, wait time.Duration)
+	// ObserveUpdateQueue registers depth, which returns the number of
+	// updates from source, either UpdateSourcePolling or UpdateSourceWebhook,
+	// waiting to be read from the updates channel. It is called once the
+	// channel is created, and depth should be called when metrics are
+	// collected, replacing any earlier depth for source.
+	ObserveUpdateQueue(source string, depth func() int)
+}
+
+// requestOutcome returns the outcome of a request that returned err.
+func requestOutcome(err error) string {
+	var apiErr *Error
+	switch {
+	case err == nil:
+		return OutcomeOK
+	case errors.As(err, &apiErr) && apiErr.Code != 0:
+		return strconv.Itoa(apiErr.Code)
+	case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+		return OutcomeCanceled
+	default:
+		return OutcomeError
+	}
+}
+
+// observeRequest reports a request and its outcome to the bot's Metrics.
+func (bot *BotAPI) observeRequest(method string, latency time.Duration, uploaded int64, err error) {
+	if bot.Metrics == nil {
+		return
+	}
+
+	bot.Metrics.ObserveRequest(method, requestOutcome(err), latency)
+
+	if uploaded > 0 {
+		bot.Metrics.ObserveUploadBytes(method, uploaded)
+	}
+
+	var apiErr *Error
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		bot.Metrics.ObserveRetryAfter(method, time.Duration(apiErr.RetryAfter)*time.Second)
+	}
+}
+
+// observeUpdateQueue registers the number of updates waiting in ch with the
+// bot's Metrics.
+func (bot *BotAPI) observeUpdateQueue(source string, ch chan Update) {
+	if bot.Metrics != nil {
+		bot.Metrics.ObserveUpdateQueue(source, func() int { return len(ch) })
+	}
+}
+
+// countingReader counts the bytes read from r.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+	return n, err
+}
+
+// DefaultLatencyBuckets are the upper bounds, in seconds, of the request
+// latency histogram of PrometheusMetrics.
+var DefaultLatencyBuckets = []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// retryAfterBuckets are the upper bounds, in seconds, of the retry after
+// histogram of PrometheusMetrics.
+var retryAfterBuckets = []float64{1, 2, 5, 10, 30, 60, 300, 900, 3600}
+
+// PrometheusMetrics is a Metrics keeping measurements in memory, which it
+// serves in the Prometheus text format as an http.Handler:
+//
+//	metrics := tgbotapi.NewPrometheusMetrics()
+//	bot.Metrics = metrics
+//	http.Handle("/metrics", metrics)
+//
+// It exports the following metrics:
+//
+//   - telegram_bot_requests_total, a counter of requests by method and outcome
+//   - telegram_bot_request_duration_seconds, a histogram of request latency
+//     by method
+//   - telegram_bot_upload_bytes_total, a counter of bytes uploaded by method
+//   - telegram_bot_retry_after_seconds, a histogram of the time flood control
+//     asked to wait, by method
+//   - telegram_bot_update_queue_depth, a gauge of updates waiting to be read,
+//     by source
+type PrometheusMetrics struct {
+	// LatencyBuckets are the upper bounds, in seconds, of the latency
+	// histogram. They default to DefaultLatencyBuckets, and must not be
+	// changed once requests are observed.
+	LatencyBuckets []float64
+
+	mu          sync.Mutex
+	requests    map[[2]string]uint64
+	latency     map[string]*histogram
+	uploadBytes map[string]int64
+	retryAfter  map[string]*histogram
+	queueDepth  map[string]func() int
+}
+
+// NewPrometheusMetrics creates a PrometheusMetrics.
+func NewPrometheusMetrics() *PrometheusMetrics {
+	return &PrometheusMetrics{
+		LatencyBuckets: DefaultLatencyBuckets,
+		requests:       make(map[[2]string]uint64),
+		latency:        make(map[string]*histogram),
+		uploadBytes:    make(map[string]int64),
+		retryAfter:     make(map[string]*histogram),
+		queueDepth:     make(map[string]func() int),
+	}
+}
+
+// ObserveRequest implements Metrics.
+func (m *PrometheusMetrics) ObserveRequest(method, outcome string, latency time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests[[2]string{method, outcome}]++
+	observe(m.latency, method, m.LatencyBuckets, latency.Seconds())
+}
+
+// ObserveUploadBytes implements Metrics.
+func (m *PrometheusMetrics) ObserveUploadBytes(method string, n int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.uploadBytes[method] += n
+}
+
+// ObserveRetryAfter implements Metrics.
+func (m *PrometheusMetrics) ObserveRetryAfter(method string, wait time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	observe(m.retryAfter, method, retryAfterBuckets, wait.Seconds())
+}
+
+// ObserveUpdateQueue implements Metrics.
+func (m *PrometheusMetrics) ObserveUpdateQueue(source string, depth func() int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.queueDepth[source] = depth
+}
+
+// ServeHTTP writes the metrics in the Prometheus text format.
+func (m *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = m.WriteTo(w)
+}
+
+// WriteTo writes the metrics in the Prometheus text format to w.
+func (m *PrometheusMetrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b strings.Builder
+
+	writeHeader(&b, "telegram_bot_requests_total", "counter", "Requests made to the Bot API, by method and outcome.")
+	keys := make([][2]string, 0, len(m.requests))
+	for key := range m.requests {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	for _, key := range keys {
+		fmt.Fprintf(&b, "telegram_bot_requests_total{method=%s,outcome=%s} %d\n", quoteLabel(key[0]), quoteLabel(key[1]), m.requests[key])
+	}
+
+	writeHeader(&b, "telegram_bot_request_duration_seconds", "histogram", "Latency of requests made to the Bot API, by method.")
+	writeHistograms(&b, "telegram_bot_request_duration_seconds", "method", m.latency)
+
+	writeHeader(&b, "telegram_bot_upload_bytes_total", "counter", "Bytes uploaded to the Bot API, by method.")
+	for _, method := range sortedKeys(m.uploadBytes) {
+		fmt.Fprintf(&b, "telegram_bot_upload_bytes_total{method=%s} %d\n", quoteLabel(method), m.uploadBytes[method])
+	}
+
+	writeHeader(&b, "telegram_bot_retry_after_seconds", "histogram", "Time flood control asked to wait before retrying, by method.")
+	writeHistograms(&b, "telegram_bot_retry_after_seconds", "method", m.retryAfter)
+
+	writeHeader(&b, "telegram_bot_update_queue_depth", "gauge", "Updates waiting to be read from the updates channel, by source.")
+	for _, source := range sortedKeys(m.queueDepth) {
+		fmt.Fprintf(&b, "telegram_bot_update_queue_depth{source=%s} %d\n", quoteLabel(source), m.queueDepth[source]())
+	}
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// histogram counts observations in cumulative buckets.
+type histogram struct {
+	bounds []float64
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// observe adds value to the histogram for key in histograms, creating it with
+// bounds if needed.
+func observe(histograms map[string]*histogram, key string, bounds []float64, value float64) {
+	h, ok := histograms[key]
+	if !ok {
+		h = &histogram{bounds: bounds, counts: make([]uint64, len(bounds))}
+		histograms[key] = h
+	}
+
+	for i, bound := range h.bounds {
+		if value <= bound {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += value
+}
+
+func writeHeader(b *strings.Builder, name, kind, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func writeHistograms(b *strings.Builder, name, label string, histograms map[string]*histogram) {
+	for _, key := range sortedKeys(histograms) {
+		h := histograms[key]
+		value := quoteLabel(key)
+
+		for i, bound := range h.bounds {
+			fmt.Fprintf(b, "%s_bucket{%s=%s,le=\"%s\"} %d\n", name, label, value, formatFloat(bound), h.counts[i])
+		}
+		fmt.Fprintf(b, "%s_bucket{%s=%s,le=\"+Inf\"} %d\n", name, label, value, h.count)
+		fmt.Fprintf(b, "%s_sum{%s=%s} %s\n", name, label, value, formatFloat(h.sum))
+		fmt.Fprintf(b, "%s_count{%s=%s} %d\n", name, label, value, h.count)
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// quoteLabel quotes a label value, escaping it as required by the Prometheus
+// text format.
+func quoteLabel(value string) string {
+	value = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+	return `"` + value + `"`
+}
+
+func formatFloat(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,113 @@
+package tgbotapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrometheusMetrics(t *testing.T) {
+	bot := newLocalBot(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/sendMessage"):
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 7","parameters":{"retry_after":7}}`))
+		case strings.HasSuffix(r.URL.Path, "/sendDocument"):
+			writeResult(w, `{"message_id":1}`)
+		default:
+			writeResult(w, "true")
+		}
+	})
+
+	metrics := NewPrometheusMetrics()
+	bot.Metrics = metrics
+
+	if _, err := bot.Request(NewChatAction(1, ChatTyping)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bot.Send(NewMessage(1, "text")); err == nil {
+		t.Fatal("expected a flood control error")
+	}
+	if _, err := bot.Send(NewDocument(1, FileBytes{Name: "file.txt", Bytes: []byte("data")})); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _ = bot.RequestWithContext(ctx, NewChatAction(1, ChatTyping))
+
+	w := httptest.NewRecorder()
+	metrics.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	out := w.Body.String()
+	for _, expected := range []string{
+		"# TYPE telegram_bot_requests_total counter\n",
+		`telegram_bot_requests_total{method="sendChatAction",outcome="ok"} 1` + "\n",
+		`telegram_bot_requests_total{method="sendChatAction",outcome="canceled"} 1` + "\n",
+		`telegram_bot_requests_total{method="sendMessage",outcome="429"} 1` + "\n",
+		`telegram_bot_requests_total{method="sendDocument",outcome="ok"} 1` + "\n",
+		`telegram_bot_request_duration_seconds_count{method="sendChatAction"} 2` + "\n",
+		`telegram_bot_request_duration_seconds_bucket{method="sendMessage",le="+Inf"} 1` + "\n",
+		`telegram_bot_retry_after_seconds_bucket{method="sendMessage",le="5"} 0` + "\n",
+		`telegram_bot_retry_after_seconds_bucket{method="sendMessage",le="10"} 1` + "\n",
+		`telegram_bot_retry_after_seconds_sum{method="sendMessage"} 7` + "\n",
+		`telegram_bot_upload_bytes_total{method="sendDocument"} `,
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected metrics to contain %q:\n%s", expected, out)
+		}
+	}
+
+	if strings.Contains(out, `telegram_bot_upload_bytes_total{method="sendDocument"} 0`) {
+		t.Errorf("expected uploaded bytes to be counted:\n%s", out)
+	}
+}
+
+func TestUpdateQueueMetrics(t *testing.T) {
+	bot := newLocalBot(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("offset") == "" {
+			writeResult(w, `[{"update_id":1},{"update_id":2},{"update_id":3}]`)
+			return
+		}
+
+		<-r.Context().Done()
+	})
+
+	metrics := NewPrometheusMetrics()
+	bot.Metrics = metrics
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	updates := bot.GetUpdatesChanWithContext(ctx, NewUpdate(0))
+
+	depth := func() string {
+		var b strings.Builder
+		_, _ = metrics.WriteTo(&b)
+		return b.String()
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for !strings.Contains(depth(), `telegram_bot_update_queue_depth{source="polling"} 3`) {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected a queue depth of 3:\n%s", depth())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// The depth is sampled when metrics are collected, not only when
+	// updates are received.
+	<-updates
+	if out := depth(); !strings.Contains(out, `telegram_bot_update_queue_depth{source="polling"} 2`) {
+		t.Errorf("expected a queue depth of 2 after reading an update:\n%s", out)
+	}
+}
+
+func TestQuoteLabel(t *testing.T) {
+	if quoted := quoteLabel("a\"b\\c\nd"); quoted != `"a\"b\\c\nd"` {
+		t.Errorf("unexpected quoted label %s", quoted)
+	}
+}
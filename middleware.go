@@ -0,0 +1,73 @@
+package tgbotapi
+
+import "context"
+
+// APIRequest is a single call to a Bot API method as it passes through the
+// bot's middleware.
+type APIRequest struct {
+	// Endpoint is the name of the Bot API method, such as "sendMessage".
+	Endpoint string
+	// Params are the parameters of the request.
+	Params Params
+	// Files are the files to upload with the request. Requests without
+	// files are form encoded, requests with files use a multipart body.
+	Files []RequestFile
+
+	// types holds the JSON type of the params set by configs, used by
+	// EncodingJSON.
+	types paramTypes
+}
+
+// RequestHandler performs an APIRequest and returns Telegram's response.
+//
+// When Telegram reports a failure, the returned error is an *Error.
+type RequestHandler func(ctx context.Context, req *APIRequest) (*APIResponse, error)
+
+// RequestMiddleware wraps a RequestHandler to add behaviour around API calls,
+// such as logging, metrics, caching or changing requests before they are sent.
+//
+// Middleware may modify the request before calling next, and inspect or
+// replace the response and error it returns. It may also return without
+// calling next at all.
+type RequestMiddleware func(next RequestHandler) RequestHandler
+
+// Use adds middleware that is called for every request made by the bot,
+// including MakeRequest and UploadFiles. The first middleware added is the
+// outermost one.
+//
+// Middleware sees each call once; retries and rate limiting happen after the
+// middleware chain. Use is not safe to call concurrently with requests.
+func (bot *BotAPI) Use(middleware ...RequestMiddleware) {
+	bot.middleware = append(bot.middleware, middleware...)
+}
+
+// handle passes req through the bot's middleware and performs it, within a
+// telegram.request span.
+func (bot *BotAPI) handle(ctx context.Context, req *APIRequest) (*APIResponse, error) {
+	ctx, span := bot.startSpan(ctx, "telegram.request", requestAttrs(req)...)
+
+	handler := RequestHandler(bot.perform)
+	for i := len(bot.middleware) - 1; i >= 0; i-- {
+		handler = bot.middleware[i](handler)
+	}
+
+	resp, err := handler(ctx, req)
+	endSpan(span, err)
+
+	return resp, err
+}
+
+// perform is the final RequestHandler, which sends the request to Telegram.
+func (bot *BotAPI) perform(ctx context.Context, req *APIRequest) (*APIResponse, error) {
+	if resp, ok := bot.performDryRun(ctx, req); ok {
+		return resp, nil
+	}
+
+	if len(req.Files) > 0 {
+		return bot.uploadFiles(ctx, req.Endpoint, req.Params, req.Files)
+	}
+
+	return bot.withRetry(ctx, req.Endpoint, func(ctx context.Context) (*APIResponse, error) {
+		return bot.makeRequest(ctx, req.Endpoint, req.Params, req.types)
+	})
+}
@@ -0,0 +1,96 @@
+package tgbotapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestMiddlewareOrderAndMutation(t *testing.T) {
+	var received string
+	bot := newLocalBot(t, func(w http.ResponseWriter, r *http.Request) {
+		received = r.FormValue("business_connection_id")
+		writeResult(w, `{"message_id":1,"date":0,"chat":{"id":1,"type":"private"}}`)
+	})
+
+	var order []string
+	trace := func(name string) RequestMiddleware {
+		return func(next RequestHandler) RequestHandler {
+			return func(ctx context.Context, req *APIRequest) (*APIResponse, error) {
+				order = append(order, name+":"+req.Endpoint)
+				return next(ctx, req)
+			}
+		}
+	}
+
+	bot.Use(trace("first"), trace("second"))
+	bot.Use(func(next RequestHandler) RequestHandler {
+		return func(ctx context.Context, req *APIRequest) (*APIResponse, error) {
+			req.Params["business_connection_id"] = "conn"
+			return next(ctx, req)
+		}
+	})
+
+	if _, err := bot.Send(NewMessage(1, "hello")); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(order) != 2 || order[0] != "first:sendMessage" || order[1] != "second:sendMessage" {
+		t.Errorf("unexpected middleware order %v", order)
+	}
+	if received != "conn" {
+		t.Errorf("expected middleware to add business_connection_id, got %q", received)
+	}
+}
+
+func TestMiddlewareSeesErrorsAndFiles(t *testing.T) {
+	bot := newLocalBot(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = io.WriteString(w, `{"ok":false,"error_code":403,"description":"Forbidden: bot was blocked by the user"}`)
+	})
+
+	var (
+		files  int
+		apiErr *Error
+	)
+	bot.Use(func(next RequestHandler) RequestHandler {
+		return func(ctx context.Context, req *APIRequest) (*APIResponse, error) {
+			files = len(req.Files)
+			resp, err := next(ctx, req)
+			errors.As(err, &apiErr)
+			return resp, err
+		}
+	})
+
+	_, err := bot.Send(NewDocument(1, FileBytes{Name: "a.txt", Bytes: []byte("a")}))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if files != 1 {
+		t.Errorf("expected middleware to see 1 file, got %d", files)
+	}
+	if apiErr == nil || apiErr.Message != "Forbidden: bot was blocked by the user" {
+		t.Errorf("expected middleware to see the API error, got %v", apiErr)
+	}
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	bot := newLocalBot(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request should not reach the server")
+	})
+	bot.Use(func(next RequestHandler) RequestHandler {
+		return func(ctx context.Context, req *APIRequest) (*APIResponse, error) {
+			return &APIResponse{Ok: true, Result: []byte(`{"id":7,"is_bot":true,"first_name":"cached"}`)}, nil
+		}
+	})
+
+	me, err := bot.GetMe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if me.ID != 7 {
+		t.Errorf("expected cached user, got %+v", me)
+	}
+}
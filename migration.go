@@ -0,0 +1,187 @@
+package tgbotapi
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+)
+
+// ChatMigrations keeps track of groups that were upgraded to supergroups.
+//
+// When a group is upgraded, it gets a new chat ID and requests using the old
+// one fail. ChatMigrations learns the new IDs from these failures and from
+// the service messages Telegram sends when a group is migrated, and rewrites
+// the chat IDs of following requests.
+//
+// It is safe for concurrent use.
+type ChatMigrations struct {
+	mu        sync.RWMutex
+	chats     map[int64]int64
+	onMigrate func(from, to int64)
+}
+
+// NewChatMigrations creates a ChatMigrations. The onMigrate callback, if not
+// nil, is called once for every migration learned, so applications can update
+// chat IDs they stored.
+func NewChatMigrations(onMigrate func(from, to int64)) *ChatMigrations {
+	return &ChatMigrations{
+		chats:     make(map[int64]int64),
+		onMigrate: onMigrate,
+	}
+}
+
+// Lookup returns the chat ID a group was migrated to.
+func (m *ChatMigrations) Lookup(chatID int64) (int64, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	to, ok := m.chats[chatID]
+	return to, ok
+}
+
+// Add records that the group from was migrated to the supergroup to.
+func (m *ChatMigrations) Add(from, to int64) {
+	if from == 0 || to == 0 || from == to {
+		return
+	}
+
+	m.mu.Lock()
+	known, ok := m.chats[from]
+	m.chats[from] = to
+	m.mu.Unlock()
+
+	if m.onMigrate != nil && (!ok || known != to) {
+		m.onMigrate(from, to)
+	}
+}
+
+// ObserveUpdate records migrations announced by service messages in update.
+func (m *ChatMigrations) ObserveUpdate(update Update) {
+	message := update.Message
+	if message == nil || message.Chat == nil {
+		return
+	}
+
+	if message.MigrateToChatID != 0 {
+		m.Add(message.Chat.ID, message.MigrateToChatID)
+	}
+
+	if message.MigrateFromChatID != 0 {
+		m.Add(message.MigrateFromChatID, message.Chat.ID)
+	}
+}
+
+// Middleware returns a RequestMiddleware that rewrites the chat_id and
+// from_chat_id params of requests to migrated groups.
+//
+// If a request fails because its chat was migrated, the migration is recorded
+// and the request is retried once with the new chat ID. Requests uploading
+// files are only retried if every file is a ReplayableFileData.
+//
+// Only a chat_id of a basic group can have been migrated. When the
+// from_chat_id is a basic group too, the error doesn't tell which chat was
+// migrated, so nothing is recorded and the request isn't retried.
+func (m *ChatMigrations) Middleware() RequestMiddleware {
+	return func(next RequestHandler) RequestHandler {
+		return func(ctx context.Context, req *APIRequest) (*APIResponse, error) {
+			m.rewrite(req)
+
+			resp, err := next(ctx, req)
+
+			var apiErr *Error
+			if !errors.As(err, &apiErr) {
+				return resp, err
+			}
+
+			to, migrated := apiErr.MigratedTo()
+			from, isChat := chatIDParam(req.Params, "chat_id")
+			if !migrated || !isChat || !isBasicGroupID(from) {
+				return resp, err
+			}
+			if source, ok := chatIDParam(req.Params, "from_chat_id"); ok && isBasicGroupID(source) {
+				// Either chat may be the migrated group.
+				return resp, err
+			}
+
+			m.Add(from, to)
+
+			if !replayableFiles(req.Files) {
+				return resp, err
+			}
+
+			m.rewrite(req)
+
+			return next(ctx, req)
+		}
+	}
+}
+
+// rewrite replaces the chat IDs of migrated groups in req's params. The
+// params are copied before being changed.
+func (m *ChatMigrations) rewrite(req *APIRequest) {
+	copied := false
+
+	for _, key := range []string{"chat_id", "from_chat_id"} {
+		chatID, ok := chatIDParam(req.Params, key)
+		if !ok {
+			continue
+		}
+
+		to, ok := m.Lookup(chatID)
+		if !ok {
+			continue
+		}
+
+		if !copied {
+			params := make(Params, len(req.Params))
+			for k, v := range req.Params {
+				params[k] = v
+			}
+			req.Params = params
+			copied = true
+		}
+
+		req.Params[key] = strconv.FormatInt(to, 10)
+	}
+}
+
+// isBasicGroupID reports whether chatID is the ID of a basic group, the only
+// chats that can be migrated. Supergroup and channel IDs are below
+// -1000000000000, and users have positive IDs.
+func isBasicGroupID(chatID int64) bool {
+	return chatID < 0 && chatID > -1000000000000
+}
+
+// chatIDParam parses a numeric chat ID param.
+func chatIDParam(params Params, key string) (int64, bool) {
+	value, ok := params[key]
+	if !ok {
+		return 0, false
+	}
+
+	chatID, err := strconv.ParseInt(value, 10, 64)
+	return chatID, err == nil
+}
+
+// EnableChatMigration makes the bot transparently follow groups that are
+// upgraded to supergroups, using a ChatMigrations that is returned.
+//
+// Migrations are learned from failed requests and from updates received by
+// GetUpdatesChan, ListenForWebhook and HandleUpdate. The onMigrate callback,
+// which may be nil, is called for every migration learned.
+//
+// It must be called before making requests.
+func (bot *BotAPI) EnableChatMigration(onMigrate func(from, to int64)) *ChatMigrations {
+	bot.migrations = NewChatMigrations(onMigrate)
+	bot.Use(bot.migrations.Middleware())
+
+	return bot.migrations
+}
+
+// observeUpdate lets the bot learn from an incoming update.
+func (bot *BotAPI) observeUpdate(update Update) {
+	if bot.migrations != nil {
+		bot.migrations.ObserveUpdate(update)
+	}
+}
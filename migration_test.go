@@ -0,0 +1,117 @@
+package tgbotapi
+
+import (
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestChatMigrationRetry(t *testing.T) {
+	var chatIDs []string
+	bot := newLocalBot(t, func(w http.ResponseWriter, r *http.Request) {
+		chatID := r.FormValue("chat_id")
+		chatIDs = append(chatIDs, chatID)
+
+		if chatID == "-100" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = io.WriteString(w, `{"ok":false,"error_code":400,"description":"Bad Request: group chat was upgraded to a supergroup chat","parameters":{"migrate_to_chat_id":-1001234}}`)
+			return
+		}
+
+		writeResult(w, `{"message_id":1,"date":0,"chat":{"id":`+chatID+`,"type":"supergroup"}}`)
+	})
+
+	var migrations [][2]int64
+	bot.EnableChatMigration(func(from, to int64) {
+		migrations = append(migrations, [2]int64{from, to})
+	})
+
+	msg, err := bot.Send(NewMessage(-100, "hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Chat.ID != -1001234 {
+		t.Errorf("expected message in the new chat, got %d", msg.Chat.ID)
+	}
+
+	if _, err := bot.Send(NewMessage(-100, "again")); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"-100", "-1001234", "-1001234"}
+	if len(chatIDs) != len(want) {
+		t.Fatalf("expected requests for %v, got %v", want, chatIDs)
+	}
+	for i := range want {
+		if chatIDs[i] != want[i] {
+			t.Errorf("expected requests for %v, got %v", want, chatIDs)
+		}
+	}
+
+	if len(migrations) != 1 || migrations[0] != [2]int64{-100, -1001234} {
+		t.Errorf("unexpected migration callbacks %v", migrations)
+	}
+}
+
+func TestChatMigrationsObserveUpdate(t *testing.T) {
+	migrations := NewChatMigrations(nil)
+
+	migrations.ObserveUpdate(Update{Message: &Message{
+		Chat:            &Chat{ID: -5},
+		MigrateToChatID: -1005,
+	}})
+	migrations.ObserveUpdate(Update{Message: &Message{
+		Chat:              &Chat{ID: -1006},
+		MigrateFromChatID: -6,
+	}})
+
+	if to, ok := migrations.Lookup(-5); !ok || to != -1005 {
+		t.Errorf("Lookup(-5) = %d, %v", to, ok)
+	}
+	if to, ok := migrations.Lookup(-6); !ok || to != -1006 {
+		t.Errorf("Lookup(-6) = %d, %v", to, ok)
+	}
+
+	req := &APIRequest{Endpoint: "forwardMessage", Params: Params{"chat_id": "-5", "from_chat_id": "-6"}}
+	original := req.Params
+	migrations.rewrite(req)
+
+	if req.Params["chat_id"] != "-1005" || req.Params["from_chat_id"] != "-1006" {
+		t.Errorf("unexpected params %v", req.Params)
+	}
+	if original["chat_id"] != "-5" {
+		t.Error("expected original params not to be modified")
+	}
+}
+
+func TestChatMigrationForwardFromMigratedGroup(t *testing.T) {
+	var requests []string
+	bot := newLocalBot(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.FormValue("chat_id")+"<"+r.FormValue("from_chat_id"))
+
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = io.WriteString(w, `{"ok":false,"error_code":400,"description":"Bad Request: group chat was upgraded to a supergroup chat","parameters":{"migrate_to_chat_id":-1001234}}`)
+	})
+
+	migrations := bot.EnableChatMigration(nil)
+
+	// The source group was migrated, not the user the message is sent to.
+	if _, err := bot.Send(NewForward(42, -100, 1)); err == nil {
+		t.Error("expected the forward to fail")
+	}
+	if _, ok := migrations.Lookup(42); ok {
+		t.Error("expected the user not to be recorded as a migrated group")
+	}
+
+	// Between two groups, the migrated one is unknown.
+	if _, err := bot.Send(NewCopyMessage(-200, -100, 1)); err == nil {
+		t.Error("expected the copy to fail")
+	}
+	if _, ok := migrations.Lookup(-200); ok {
+		t.Error("expected no migration to be recorded when the error is ambiguous")
+	}
+
+	if len(requests) != 2 {
+		t.Errorf("expected no retries, got requests %v", requests)
+	}
+}
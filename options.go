@@ -0,0 +1,184 @@
+package tgbotapi
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"regexp"
+)
+
+// ErrInvalidToken is returned by New and ValidateToken for tokens that are not
+// formatted like the ones issued by @BotFather.
+var ErrInvalidToken = errors.New("invalid bot token")
+
+// tokenPattern matches bot tokens, made of the bot's ID and a secret.
+var tokenPattern = regexp.MustCompile(`^[1-9][0-9]*:[A-Za-z0-9_-]{30,}$`)
+
+// ValidateToken checks that token is formatted like a token issued by
+// @BotFather, such as 123456:ABC-DEF1234ghIkl-zyx57W2v1u123ew11. It doesn't
+// make any request, so it can't tell if the token was revoked.
+func ValidateToken(token string) error {
+	if !tokenPattern.MatchString(token) {
+		return ErrInvalidToken
+	}
+
+	return nil
+}
+
+// Option configures a BotAPI created by New.
+type Option func(*BotAPI)
+
+// WithAPIEndpoint sets the endpoint used for requests, with formatting for
+// Sprintf like APIEndpoint. It is useful for local Bot API servers.
+func WithAPIEndpoint(apiEndpoint string) Option {
+	return func(bot *BotAPI) {
+		bot.apiEndpoint = apiEndpoint
+	}
+}
+
+// WithFileEndpoint sets the endpoint files are downloaded from, like
+// SetFileEndpoint.
+func WithFileEndpoint(fileEndpoint string) Option {
+	return func(bot *BotAPI) {
+		bot.fileEndpoint = fileEndpoint
+	}
+}
+
+// WithHTTPClient sets the client used to perform HTTP requests.
+func WithHTTPClient(client HTTPClient) Option {
+	return func(bot *BotAPI) {
+		bot.Client = client
+	}
+}
+
+// WithBuffer sets the size of the channels returned by GetUpdatesChan and
+// ListenForWebhook.
+func WithBuffer(buffer int) Option {
+	return func(bot *BotAPI) {
+		bot.Buffer = buffer
+	}
+}
+
+// WithDebug enables logging of requests and responses.
+func WithDebug(debug bool) Option {
+	return func(bot *BotAPI) {
+		bot.Debug = debug
+	}
+}
+
+// WithLogger sets the logger used by the bot instead of the package logger
+// set with SetLogger. See BotAPI.SetLogger.
+func WithLogger(logger *slog.Logger) Option {
+	return func(bot *BotAPI) {
+		bot.logger = logger
+	}
+}
+
+// WithRetryPolicy sets how failed requests are retried.
+func WithRetryPolicy(policy *RetryPolicy) Option {
+	return func(bot *BotAPI) {
+		bot.Retry = policy
+	}
+}
+
+// WithRateLimiter sets the limiter throttling requests.
+func WithRateLimiter(limiter RateLimiter) Option {
+	return func(bot *BotAPI) {
+		bot.Limiter = limiter
+	}
+}
+
+// WithEncoding sets how params are sent for requests without files to upload.
+func WithEncoding(encoding Encoding) Option {
+	return func(bot *BotAPI) {
+		bot.Encoding = encoding
+	}
+}
+
+// WithMetrics sets the Metrics receiving measurements about requests and
+// updates.
+func WithMetrics(metrics Metrics) Option {
+	return func(bot *BotAPI) {
+		bot.Metrics = metrics
+	}
+}
+
+// WithTracer sets the Tracer starting spans for updates and requests.
+func WithTracer(tracer Tracer) Option {
+	return func(bot *BotAPI) {
+		bot.Tracer = tracer
+	}
+}
+
+// WithMiddleware adds request middleware, like Use.
+func WithMiddleware(middleware ...RequestMiddleware) Option {
+	return func(bot *BotAPI) {
+		bot.Use(middleware...)
+	}
+}
+
+// WithMaxDownloadSize sets the size of the largest file the bot downloads,
+// or removes the limit if it is negative.
+func WithMaxDownloadSize(size int64) Option {
+	return func(bot *BotAPI) {
+		bot.MaxDownloadSize = size
+	}
+}
+
+// WithDryRun switches the bot to dry-run mode, passing the requests it
+// doesn't send to fn. See BotAPI.SetDryRun.
+func WithDryRun(fn DryRunFunc) Option {
+	return func(bot *BotAPI) {
+		bot.dryRun = fn
+	}
+}
+
+// WithSelf sets the bot's own user, so it is known without calling Init.
+func WithSelf(self User) Option {
+	return func(bot *BotAPI) {
+		bot.Self = self
+	}
+}
+
+// New creates a new BotAPI instance configured with opts.
+//
+// Unlike NewBotAPI, it doesn't make any request: the token is only checked
+// with ValidateToken, and Self stays empty until Init is called or it is set
+// with WithSelf. This allows creating bots in tests, or while Telegram can't
+// be reached.
+func New(token string, opts ...Option) (*BotAPI, error) {
+	if err := ValidateToken(token); err != nil {
+		return nil, err
+	}
+
+	bot := &BotAPI{
+		Token:           token,
+		Client:          &http.Client{},
+		Buffer:          100,
+		shutdownChannel: make(chan interface{}),
+
+		apiEndpoint: APIEndpoint,
+	}
+
+	for _, opt := range opts {
+		opt(bot)
+	}
+
+	return bot, nil
+}
+
+// Init fetches the bot's own user with getMe, which also checks that the
+// token is valid, and stores it in Self.
+//
+// It must not be called while the bot is used by other goroutines.
+func (bot *BotAPI) Init(ctx context.Context) error {
+	self, err := bot.GetMeWithContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	bot.Self = self
+
+	return nil
+}
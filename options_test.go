@@ -0,0 +1,102 @@
+package tgbotapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestValidateToken(t *testing.T) {
+	tests := []struct {
+		token string
+		valid bool
+	}{
+		{TestToken, true},
+		{"123456:ABC-DEF1234ghIkl-zyx57W2v1u123ew11", true},
+		{"", false},
+		{"123456", false},
+		{"123456:", false},
+		{"abc:ABC-DEF1234ghIkl-zyx57W2v1u123ew11", false},
+		{"0123:ABC-DEF1234ghIkl-zyx57W2v1u123ew11", false},
+		{"123456:short", false},
+		{"123456:ABC-DEF1234ghIkl-zyx57W2v1u123ew11 ", false},
+		{"123456:ABC/DEF1234ghIkl-zyx57W2v1u123ew11", false},
+	}
+
+	for _, test := range tests {
+		err := ValidateToken(test.token)
+		if test.valid && err != nil {
+			t.Errorf("expected %q to be valid, got %v", test.token, err)
+		}
+		if !test.valid && !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("expected %q to be invalid, got %v", test.token, err)
+		}
+	}
+}
+
+func TestNewOffline(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		writeResult(w, `{"id":123456,"is_bot":true,"first_name":"Bot","username":"test_bot"}`)
+	}))
+	defer srv.Close()
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	policy := NewRetryPolicy()
+
+	bot, err := New("123456:ABC-DEF1234ghIkl-zyx57W2v1u123ew11",
+		WithAPIEndpoint(srv.URL+"/bot%s/%s"),
+		WithHTTPClient(srv.Client()),
+		WithBuffer(5),
+		WithDebug(true),
+		WithLogger(logger),
+		WithRetryPolicy(policy),
+		WithEncoding(EncodingJSON),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if requests != 0 {
+		t.Fatalf("expected New not to make requests, made %d", requests)
+	}
+	if bot.Buffer != 5 || !bot.Debug || bot.Retry != policy || bot.Encoding != EncodingJSON {
+		t.Errorf("options were not applied: %+v", bot)
+	}
+	if bot.Self.ID != 0 {
+		t.Errorf("expected Self to be empty before Init, got %+v", bot.Self)
+	}
+
+	if err := bot.Init(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 1 || bot.Self.UserName != "test_bot" {
+		t.Errorf("expected Init to fetch Self, got %+v after %d requests", bot.Self, requests)
+	}
+
+	if !strings.Contains(logs.String(), "method=getMe") {
+		t.Errorf("expected the bot logger to be used, got %q", logs.String())
+	}
+
+	if _, err := New("invalid"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected ErrInvalidToken, got %v", err)
+	}
+}
+
+func TestNewWithSelf(t *testing.T) {
+	bot, err := New(TestToken, WithSelf(User{ID: 1, UserName: "offline_bot"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bot.IsMessageToMe(Message{Text: "/start@offline_bot"}) {
+		t.Error("expected the message to be for the bot")
+	}
+}
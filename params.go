@@ -45,9 +45,31 @@ func (p Params) AddNonZeroFloat(key string, value float64) {
 	}
 }
 
+// SetInt adds an int value, even if it is zero. It is used for params that
+// are required or for which zero is meaningful.
+func (p Params) SetInt(key string, value int) {
+	p[key] = strconv.Itoa(value)
+}
+
+// SetInt64 is the same as SetInt except uses an int64.
+func (p Params) SetInt64(key string, value int64) {
+	p[key] = strconv.FormatInt(value, 10)
+}
+
+// SetBool adds a bool value, even if it is false. It is used for params that
+// are required or that default to true.
+func (p Params) SetBool(key string, value bool) {
+	p[key] = strconv.FormatBool(value)
+}
+
+// SetFloat adds a floating point value, even if it is zero.
+func (p Params) SetFloat(key string, value float64) {
+	p[key] = strconv.FormatFloat(value, 'f', 6, 64)
+}
+
 // AddInterface adds an interface if it is not nil and can be JSON marshalled.
 func (p Params) AddInterface(key string, value interface{}) error {
-	if value == nil || (reflect.ValueOf(value).Kind() == reflect.Ptr && reflect.ValueOf(value).IsNil()) {
+	if isNilValue(value) {
 		return nil
 	}
 
@@ -61,6 +83,12 @@ func (p Params) AddInterface(key string, value interface{}) error {
 	return nil
 }
 
+// isNilValue reports whether value is nil or a nil pointer, which
+// AddInterface doesn't add.
+func isNilValue(value interface{}) bool {
+	return value == nil || (reflect.ValueOf(value).Kind() == reflect.Ptr && reflect.ValueOf(value).IsNil())
+}
+
 // AddFirstValid attempts to add the first item that is not a default value.
 //
 // For example, AddFirstValid(0, "", "test") would add "test".
@@ -106,3 +134,211 @@ func (p Params) CheckArgs(args ...string) error {
 	}
 	return nil
 }
+
+// paramType is the JSON type of a param, recorded when it is set so that
+// EncodingJSON doesn't have to guess it from the string value.
+type paramType string
+
+const (
+	paramNumber paramType = "number"
+	paramBool   paramType = "bool"
+	paramJSON   paramType = "json"
+)
+
+// paramTypes holds the JSON type of params by key. Params without a type are
+// strings.
+type paramTypes map[string]paramType
+
+// configParams are the params built by the params method of a config. Their
+// typed setters record the JSON type of the values they add, which Render
+// passes along with the request.
+type configParams struct {
+	Params
+	types paramTypes
+}
+
+// newParams creates empty config params.
+func newParams() *configParams {
+	return &configParams{Params: make(Params), types: make(paramTypes)}
+}
+
+// setType records the type of the value of key. A zero type marks it as a
+// string.
+func (p *configParams) setType(key string, t paramType) {
+	if t == "" {
+		delete(p.types, key)
+		return
+	}
+
+	p.types[key] = t
+}
+
+// AddNonEmpty is like Params.AddNonEmpty.
+func (p *configParams) AddNonEmpty(key, value string) {
+	if value != "" {
+		p.Params.AddNonEmpty(key, value)
+		p.setType(key, "")
+	}
+}
+
+// AddNonZero is like Params.AddNonZero, recording a number.
+func (p *configParams) AddNonZero(key string, value int) {
+	if value != 0 {
+		p.Params.AddNonZero(key, value)
+		p.setType(key, paramNumber)
+	}
+}
+
+// AddNonZero64 is like Params.AddNonZero64, recording a number.
+func (p *configParams) AddNonZero64(key string, value int64) {
+	if value != 0 {
+		p.Params.AddNonZero64(key, value)
+		p.setType(key, paramNumber)
+	}
+}
+
+// AddBool is like Params.AddBool, recording a bool.
+func (p *configParams) AddBool(key string, value bool) {
+	if value {
+		p.Params.AddBool(key, value)
+		p.setType(key, paramBool)
+	}
+}
+
+// AddNonZeroFloat is like Params.AddNonZeroFloat, recording a number.
+func (p *configParams) AddNonZeroFloat(key string, value float64) {
+	if value != 0 {
+		p.Params.AddNonZeroFloat(key, value)
+		p.setType(key, paramNumber)
+	}
+}
+
+// SetInt is like Params.SetInt, recording a number.
+func (p *configParams) SetInt(key string, value int) {
+	p.Params.SetInt(key, value)
+	p.setType(key, paramNumber)
+}
+
+// SetInt64 is like Params.SetInt64, recording a number.
+func (p *configParams) SetInt64(key string, value int64) {
+	p.Params.SetInt64(key, value)
+	p.setType(key, paramNumber)
+}
+
+// SetBool is like Params.SetBool, recording a bool.
+func (p *configParams) SetBool(key string, value bool) {
+	p.Params.SetBool(key, value)
+	p.setType(key, paramBool)
+}
+
+// SetFloat is like Params.SetFloat, recording a number.
+func (p *configParams) SetFloat(key string, value float64) {
+	p.Params.SetFloat(key, value)
+	p.setType(key, paramNumber)
+}
+
+// AddInterface is like Params.AddInterface, recording JSON.
+func (p *configParams) AddInterface(key string, value interface{}) error {
+	if isNilValue(value) {
+		return nil
+	}
+
+	if err := p.Params.AddInterface(key, value); err != nil {
+		return err
+	}
+	p.setType(key, paramJSON)
+
+	return nil
+}
+
+// AddFirstValid is like Params.AddFirstValid, recording the type of the
+// value added.
+func (p *configParams) AddFirstValid(key string, args ...interface{}) error {
+	for _, arg := range args {
+		var t paramType
+		switch v := arg.(type) {
+		case int:
+			if v == 0 {
+				continue
+			}
+			t = paramNumber
+		case int64:
+			if v == 0 {
+				continue
+			}
+			t = paramNumber
+		case string:
+			if v == "" {
+				continue
+			}
+		case nil:
+			continue
+		default:
+			t = paramJSON
+		}
+
+		if err := p.Params.AddFirstValid(key, arg); err != nil {
+			return err
+		}
+		p.setType(key, t)
+
+		return nil
+	}
+
+	return nil
+}
+
+// Encoding is how the params of requests without files to upload are sent to
+// Telegram. Requests uploading files always use multipart/form-data.
+type Encoding int
+
+const (
+	// EncodingForm sends params as application/x-www-form-urlencoded, with
+	// every value being a string. It is the default.
+	EncodingForm Encoding = iota
+	// EncodingJSON sends params as an application/json object. Params set
+	// by the typed setters of configs keep their type: objects and arrays,
+	// such as the ones added by AddInterface, are embedded instead of being
+	// encoded as strings, and numbers and booleans stay numbers and booleans.
+	// Any other value, including the params of MakeRequest, is a string.
+	EncodingJSON
+)
+
+// encode returns the body of a request with params and its content type.
+// types holds the JSON type of the params, which may be nil.
+func (e Encoding) encode(params Params, types paramTypes) ([]byte, string, error) {
+	if e != EncodingJSON {
+		return []byte(buildParams(params).Encode()), "application/x-www-form-urlencoded", nil
+	}
+
+	values := make(map[string]json.RawMessage, len(params))
+	for key, value := range params {
+		values[key] = jsonValue(value, types[key])
+	}
+
+	body, err := json.Marshal(values)
+	return body, "application/json", err
+}
+
+// jsonValue returns the JSON representation of a param of type t. Values are
+// only embedded as is if they are valid for their type, as middleware may have
+// replaced them, and encoded as strings otherwise.
+func jsonValue(value string, t paramType) json.RawMessage {
+	valid := false
+	switch t {
+	case paramNumber:
+		_, err := strconv.ParseFloat(value, 64)
+		valid = err == nil && json.Valid([]byte(value))
+	case paramBool:
+		valid = value == "true" || value == "false"
+	case paramJSON:
+		valid = json.Valid([]byte(value))
+	}
+
+	if valid {
+		return json.RawMessage(value)
+	}
+
+	b, _ := json.Marshal(value)
+	return b
+}
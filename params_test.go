@@ -1,6 +1,9 @@
 package tgbotapi
 
 import (
+	"encoding/json"
+	"net/http"
+	"strings"
 	"testing"
 )
 
@@ -91,3 +94,109 @@ func TestAddFirstValid(t *testing.T) {
 	assertLen(t, params, 2)
 	assertEq(t, params["value2"], "3")
 }
+
+func TestSetZeroValues(t *testing.T) {
+	params := make(Params)
+	params.SetInt("int", 0)
+	params.SetInt64("int64", 0)
+	params.SetBool("bool", false)
+	params.SetFloat("float", 0)
+	assertLen(t, params, 4)
+	assertEq(t, params["int"], "0")
+	assertEq(t, params["int64"], "0")
+	assertEq(t, params["bool"], "false")
+	assertEq(t, params["float"], "0.000000")
+}
+
+func TestEncodingJSON(t *testing.T) {
+	params := newParams()
+	params.SetInt64("chat_id", -100123)
+	params.SetBool("disable_notification", false)
+	params.AddNonEmpty("text", "42")
+	params.AddNonEmpty("caption", `"quoted"`)
+	params.AddNonEmpty("query", "null")
+	params.AddNonEmpty("media", "attach://file-0")
+	params.AddInterface("reply_markup", NewInlineKeyboardMarkup(
+		NewInlineKeyboardRow(NewInlineKeyboardButtonData("a", "b")),
+	))
+
+	params.SetInt("message_thread_id", 7)
+	params.AddNonEmpty("message_thread_id", "general")
+
+	params.AddFirstValid("user_id", 0, int64(5))
+	params.AddFirstValid("from_chat_id", 0, "@source")
+	assertLen(t, params.Params, 10)
+
+	// Middleware replacing a number with something else.
+	params.Params["chat_id"] = "@channel"
+
+	body, contentType, err := EncodingJSON.encode(params.Params, params.types)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEq(t, contentType, "application/json")
+
+	expected := `{"caption":"\"quoted\"","chat_id":"@channel","disable_notification":false,` +
+		`"from_chat_id":"@source","media":"attach://file-0","message_thread_id":"general","query":"null",` +
+		`"reply_markup":{"inline_keyboard":[[{"text":"a","callback_data":"b"}]]},"text":"42","user_id":5}`
+	assertEq(t, string(body), expected)
+
+	// Params without types, such as the ones of MakeRequest, are strings.
+	body, _, err = EncodingJSON.encode(Params{"chat_id": "1", "disable_notification": "true"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEq(t, string(body), `{"chat_id":"1","disable_notification":"true"}`)
+
+	body, contentType, err = EncodingForm.encode(Params{"chat_id": "1"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEq(t, contentType, "application/x-www-form-urlencoded")
+	assertEq(t, string(body), "chat_id=1")
+}
+
+func TestBotEncodingJSON(t *testing.T) {
+	var contentTypes []string
+
+	bot := newLocalBot(t, func(w http.ResponseWriter, r *http.Request) {
+		contentTypes = append(contentTypes, strings.Split(r.Header.Get("Content-Type"), ";")[0])
+
+		if r.Header.Get("Content-Type") == "application/json" {
+			var body map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("invalid JSON body: %v", err)
+			}
+			switch {
+			case strings.HasSuffix(r.URL.Path, "/setStickerPositionInSet"):
+				if body["position"] != float64(0) {
+					t.Errorf("expected position 0, got %v", body["position"])
+				}
+			case strings.HasSuffix(r.URL.Path, "/sendMessage"):
+				if body["chat_id"] != float64(1) || body["text"] != "42" {
+					t.Errorf("expected chat_id 1 and text \"42\", got %v and %#v", body["chat_id"], body["text"])
+				}
+			}
+		}
+
+		writeResult(w, "true")
+	})
+	bot.Encoding = EncodingJSON
+
+	if _, err := bot.Request(SetStickerPositionInSetConfig{Sticker: "id", Position: 0}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := bot.Request(NewMessage(1, "42")); err != nil {
+		t.Fatal(err)
+	}
+
+	document := NewDocument(1, FileBytes{Name: "file.txt", Bytes: []byte("data")})
+	if _, err := bot.Request(document); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(contentTypes) != 3 || contentTypes[0] != "application/json" || contentTypes[2] != "multipart/form-data" {
+		t.Errorf("unexpected content types %v", contentTypes)
+	}
+}
@@ -0,0 +1,122 @@
+package tgbotapi
+
+import (
+	"context"
+	"io"
+	"os"
+)
+
+// UploadProgress describes how much of a file has been uploaded.
+type UploadProgress struct {
+	// Method is the Bot API method the file is uploaded with.
+	Method string
+	// Field is the name of the file part, such as "document" or
+	// "file-0" for media groups.
+	Field string
+	// FileName is the name of the file sent to Telegram.
+	FileName string
+	// Written is the number of bytes of the file uploaded so far.
+	Written int64
+	// Total is the size of the file, or -1 if it isn't known, such as for a
+	// FileReader that doesn't report its length.
+	Total int64
+}
+
+// Done reports whether the whole file has been uploaded.
+func (p UploadProgress) Done() bool {
+	return p.Total >= 0 && p.Written >= p.Total
+}
+
+// Percent returns the percentage of the file uploaded, or -1 if the size of
+// the file isn't known.
+func (p UploadProgress) Percent() int {
+	if p.Total < 0 {
+		return -1
+	}
+	if p.Total == 0 {
+		return 100
+	}
+
+	return int(p.Written * 100 / p.Total)
+}
+
+// UploadProgressFunc receives the progress of uploads.
+type UploadProgressFunc func(UploadProgress)
+
+type uploadProgressKey struct{}
+
+// ContextWithUploadProgress returns a copy of ctx that makes uploads performed
+// with it report their progress to fn:
+//
+//	ctx, cancel := context.WithCancel(ctx)
+//	ctx = tgbotapi.ContextWithUploadProgress(ctx, func(p tgbotapi.UploadProgress) {
+//		log.Printf("uploading %s: %d%%", p.FileName, p.Percent())
+//	})
+//	bot.SendWithContext(ctx, tgbotapi.NewDocument(chatID, tgbotapi.FilePath("video.mp4")))
+//
+// fn is called from the goroutine writing the request body, once when each
+// file part starts and after every chunk of it is written, so it should
+// return quickly. Cancelling ctx aborts the upload mid-stream.
+func ContextWithUploadProgress(ctx context.Context, fn UploadProgressFunc) context.Context {
+	return context.WithValue(ctx, uploadProgressKey{}, fn)
+}
+
+// uploadProgressFromContext returns the UploadProgressFunc of ctx, or nil.
+func uploadProgressFromContext(ctx context.Context) UploadProgressFunc {
+	fn, _ := ctx.Value(uploadProgressKey{}).(UploadProgressFunc)
+	return fn
+}
+
+// progressWriter reports the bytes written to w to fn. Writes to the
+// multipart body block until the HTTP client consumes them, so the progress
+// follows what was actually sent.
+type progressWriter struct {
+	w        io.Writer
+	fn       UploadProgressFunc
+	progress UploadProgress
+}
+
+func (pw *progressWriter) Write(p []byte) (int, error) {
+	n, err := pw.w.Write(p)
+	if n > 0 {
+		pw.progress.Written += int64(n)
+		pw.fn(pw.progress)
+	}
+
+	return n, err
+}
+
+// uploadSize returns the size of the file data to upload, read from reader, or
+// -1 if it isn't known.
+func uploadSize(data RequestFileData, reader io.Reader) int64 {
+	if replayable, ok := data.(ReplayableFileData); ok {
+		if size := replayable.UploadSize(); size >= 0 {
+			return size
+		}
+	}
+
+	return readerSize(reader)
+}
+
+// readerSize returns the number of bytes left to read from r, or -1 if it
+// can't be known without reading.
+func readerSize(r io.Reader) int64 {
+	switch r := r.(type) {
+	case interface{ Len() int }:
+		return int64(r.Len())
+	case *os.File:
+		info, err := r.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return -1
+		}
+
+		offset, err := r.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+
+		return info.Size() - offset
+	default:
+		return -1
+	}
+}
@@ -0,0 +1,108 @@
+package tgbotapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestUploadProgress(t *testing.T) {
+	bot := newLocalBot(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		writeResult(w, `{"message_id":1}`)
+	})
+
+	data := bytes.Repeat([]byte("a"), 200*1024)
+	path := filepath.Join(t.TempDir(), "video.mp4")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	fsys := fstest.MapFS{"video.mp4": {Data: data}}
+
+	files := []RequestFileData{
+		FileBytes{Name: "video.mp4", Bytes: data},
+		FilePath(path),
+		FileReaderAt{Name: "video.mp4", Reader: bytes.NewReader(data), Size: int64(len(data))},
+		FileFS{FS: fsys, Path: "video.mp4"},
+	}
+
+	for _, file := range files {
+		var progress []UploadProgress
+		ctx := ContextWithUploadProgress(context.Background(), func(p UploadProgress) {
+			progress = append(progress, p)
+		})
+
+		if _, err := bot.SendWithContext(ctx, NewVideo(1, file)); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(progress) < 2 {
+			t.Fatalf("expected the progress to be reported several times, got %+v", progress)
+		}
+
+		first, last := progress[0], progress[len(progress)-1]
+		if first.Method != "sendVideo" || first.Field != "video" || !strings.HasSuffix(first.FileName, "video.mp4") || first.Written != 0 {
+			t.Errorf("unexpected first progress %+v", first)
+		}
+		if first.Total != int64(len(data)) {
+			t.Errorf("expected the size of %T to be known, got %+v", file, first)
+		}
+		if last.Total != int64(len(data)) || !last.Done() || last.Percent() != 100 {
+			t.Errorf("unexpected last progress %+v", last)
+		}
+
+		for i := 1; i < len(progress); i++ {
+			if progress[i].Written < progress[i-1].Written {
+				t.Errorf("expected the progress to increase, got %+v", progress)
+				break
+			}
+		}
+	}
+}
+
+func TestUploadProgressCancel(t *testing.T) {
+	bot := newLocalBot(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		writeResult(w, `{"message_id":1}`)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var last UploadProgress
+	ctx = ContextWithUploadProgress(ctx, func(p UploadProgress) {
+		last = p
+		if p.Written > 64*1024 {
+			cancel()
+		}
+	})
+
+	// A reader that doesn't report its length, and never ends.
+	reader := io.MultiReader(strings.NewReader("start"), neverEnding('a'))
+
+	_, err := bot.SendWithContext(ctx, NewDocument(1, FileReader{Name: "stream.bin", Reader: reader}))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the upload to be canceled, got %v", err)
+	}
+	if last.Total != -1 || last.Percent() != -1 || last.Done() {
+		t.Errorf("expected the size to be unknown, got %+v", last)
+	}
+}
+
+type neverEnding byte
+
+func (b neverEnding) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = byte(b)
+	}
+
+	return len(p), nil
+}
@@ -0,0 +1,293 @@
+package tgbotapi
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimiter throttles requests before they are sent to Telegram.
+//
+// Wait is called before every request attempt, including retries, with the
+// API method and the final request params.
+type RateLimiter interface {
+	// Wait blocks until the request may be sent or ctx is done, in which case
+	// it returns the context's error.
+	Wait(ctx context.Context, method string, params Params) error
+}
+
+// Priority is the priority of a request waiting in a RateLimiter.
+type Priority int
+
+// Request priorities understood by ChatRateLimiter.
+const (
+	// PriorityLow is for bulk traffic such as broadcasts.
+	PriorityLow Priority = -1
+	// PriorityNormal is the default priority.
+	PriorityNormal Priority = 0
+	// PriorityHigh is for interactive traffic, such as replies to users.
+	PriorityHigh Priority = 1
+)
+
+type priorityKey struct{}
+
+// WithPriority returns a context that makes requests using it wait in the
+// given priority lane of the bot's RateLimiter.
+func WithPriority(ctx context.Context, priority Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, priority)
+}
+
+// PriorityFromContext returns the request priority stored in ctx, or
+// PriorityNormal if there is none.
+func PriorityFromContext(ctx context.Context) Priority {
+	if priority, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		return priority
+	}
+
+	return PriorityNormal
+}
+
+// Rate is a number of requests allowed per time period. Up to Requests may be
+// sent at once after a period of inactivity.
+type Rate struct {
+	Requests int
+	Per      time.Duration
+}
+
+// RateLimits are the rates a ChatRateLimiter allows requests to be sent at.
+type RateLimits struct {
+	// Global applies to all requests made by the bot.
+	Global Rate
+	// Private applies to requests for each private chat.
+	Private Rate
+	// Group applies to requests for each group, supergroup or channel.
+	Group Rate
+}
+
+// DefaultRateLimits are the limits documented by Telegram.
+//
+// See https://core.telegram.org/bots/faq#my-bot-is-hitting-limits-how-do-i-avoid-this
+var DefaultRateLimits = RateLimits{
+	Global:  Rate{Requests: 30, Per: time.Second},
+	Private: Rate{Requests: 1, Per: time.Second},
+	Group:   Rate{Requests: 20, Per: time.Minute},
+}
+
+// maxIdleChats is the number of chat buckets kept before idle ones are
+// discarded.
+const maxIdleChats = 1024
+
+// ChatRateLimiter is a RateLimiter that keeps a global token bucket and a
+// token bucket for each chat, keyed by the chat_id of the request.
+//
+// Methods starting with "get" are never throttled, as they do not send
+// anything to users. While requests with a higher Priority are waiting, lower
+// priority requests are not sent.
+type ChatRateLimiter struct {
+	limits RateLimits
+
+	mu      sync.Mutex
+	global  *tokenBucket
+	chats   map[string]*tokenBucket
+	waiters map[*rateWaiter]struct{}
+	wake    chan struct{}
+}
+
+type rateWaiter struct {
+	priority Priority
+	chat     string
+}
+
+// NewRateLimiter creates a ChatRateLimiter with the given limits. A zero Rate
+// disables the matching limit.
+func NewRateLimiter(limits RateLimits) *ChatRateLimiter {
+	return &ChatRateLimiter{
+		limits:  limits,
+		global:  newTokenBucket(limits.Global),
+		chats:   make(map[string]*tokenBucket),
+		waiters: make(map[*rateWaiter]struct{}),
+		wake:    make(chan struct{}),
+	}
+}
+
+// Wait implements RateLimiter.
+func (l *ChatRateLimiter) Wait(ctx context.Context, method string, params Params) error {
+	if strings.HasPrefix(method, "get") {
+		return nil
+	}
+
+	w := &rateWaiter{
+		priority: PriorityFromContext(ctx),
+		chat:     params["chat_id"],
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.waiters[w] = struct{}{}
+	defer func() {
+		delete(l.waiters, w)
+		l.broadcast()
+	}()
+
+	for {
+		now := time.Now()
+		chat := l.chatBucket(w.chat)
+
+		delay := max(l.global.delay(now), chat.delay(now))
+		if delay == 0 && !l.preempted(w, now) {
+			l.global.take()
+			chat.take()
+			return nil
+		}
+
+		if delay == 0 {
+			// A higher priority request is ready to be sent. Check again once
+			// it has taken its tokens, or after a short while in case it is
+			// still waiting on its own chat.
+			delay = max(l.global.interval(), time.Millisecond)
+		}
+
+		wake := l.wake
+		l.mu.Unlock()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			l.mu.Lock()
+			return ctx.Err()
+		case <-wake:
+			timer.Stop()
+		case <-timer.C:
+		}
+
+		l.mu.Lock()
+	}
+}
+
+// preempted returns true if a higher priority request than w is waiting and
+// could be sent now.
+func (l *ChatRateLimiter) preempted(w *rateWaiter, now time.Time) bool {
+	for other := range l.waiters {
+		if other.priority > w.priority && l.chatReady(other.chat, now) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// broadcast wakes up all waiting requests.
+func (l *ChatRateLimiter) broadcast() {
+	close(l.wake)
+	l.wake = make(chan struct{})
+}
+
+// chatBucket returns the token bucket for a chat_id param. Requests without a
+// chat have an unlimited bucket.
+func (l *ChatRateLimiter) chatBucket(chatID string) *tokenBucket {
+	if chatID == "" {
+		return unlimitedBucket
+	}
+
+	if bucket, ok := l.chats[chatID]; ok {
+		return bucket
+	}
+
+	if len(l.chats) >= maxIdleChats {
+		now := time.Now()
+		for id, bucket := range l.chats {
+			if bucket.idle(now) {
+				delete(l.chats, id)
+			}
+		}
+	}
+
+	rate := l.limits.Group
+	if id, err := strconv.ParseInt(chatID, 10, 64); err == nil && id > 0 {
+		rate = l.limits.Private
+	}
+
+	bucket := newTokenBucket(rate)
+	l.chats[chatID] = bucket
+
+	return bucket
+}
+
+// chatReady returns true if a request for a chat_id param could be sent now
+// as far as its chat bucket is concerned. Unlike chatBucket, it doesn't create
+// or discard buckets, and chats without one have tokens available.
+func (l *ChatRateLimiter) chatReady(chatID string, now time.Time) bool {
+	bucket, ok := l.chats[chatID]
+	return !ok || bucket.delay(now) == 0
+}
+
+// unlimitedBucket is a token bucket that never makes requests wait.
+var unlimitedBucket = newTokenBucket(Rate{})
+
+// tokenBucket is a token bucket that is refilled at a constant rate up to a
+// burst size. It is not safe for concurrent use.
+type tokenBucket struct {
+	rate   Rate
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate Rate) *tokenBucket {
+	return &tokenBucket{rate: rate, tokens: float64(rate.Requests)}
+}
+
+func (b *tokenBucket) unlimited() bool {
+	return b.rate.Requests <= 0 || b.rate.Per <= 0
+}
+
+// interval returns the time it takes to refill a single token.
+func (b *tokenBucket) interval() time.Duration {
+	if b.unlimited() {
+		return 0
+	}
+
+	return b.rate.Per / time.Duration(b.rate.Requests)
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if !b.last.IsZero() {
+		elapsed := now.Sub(b.last)
+		b.tokens += float64(elapsed) / float64(b.interval())
+		if burst := float64(b.rate.Requests); b.tokens > burst {
+			b.tokens = burst
+		}
+	}
+	b.last = now
+}
+
+// delay returns how long to wait until a token is available.
+func (b *tokenBucket) delay(now time.Time) time.Duration {
+	if b.unlimited() {
+		return 0
+	}
+
+	b.refill(now)
+	if b.tokens >= 1 {
+		return 0
+	}
+
+	return time.Duration((1 - b.tokens) * float64(b.interval()))
+}
+
+// take removes a token from the bucket. It must only be called after delay
+// returned zero.
+func (b *tokenBucket) take() {
+	if !b.unlimited() {
+		b.tokens--
+	}
+}
+
+// idle returns true if the bucket is full, so discarding it doesn't change
+// how requests are throttled.
+func (b *tokenBucket) idle(now time.Time) bool {
+	return b.delay(now) == 0 && b.tokens >= float64(b.rate.Requests)
+}
@@ -0,0 +1,154 @@
+package tgbotapi
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterPerChat(t *testing.T) {
+	limiter := NewRateLimiter(RateLimits{
+		Private: Rate{Requests: 1, Per: 100 * time.Millisecond},
+	})
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := limiter.Wait(ctx, "sendMessage", Params{"chat_id": "1"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("expected requests to the same chat to be throttled, took %s", elapsed)
+	}
+
+	start = time.Now()
+	for i := 2; i < 10; i++ {
+		if err := limiter.Wait(ctx, "sendMessage", Params{"chat_id": strconv.Itoa(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("expected requests to different chats not to wait, took %s", elapsed)
+	}
+}
+
+func TestRateLimiterSkipsGetters(t *testing.T) {
+	limiter := NewRateLimiter(RateLimits{
+		Global: Rate{Requests: 1, Per: time.Hour},
+	})
+
+	for i := 0; i < 5; i++ {
+		if err := limiter.Wait(context.Background(), "getUpdates", nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestRateLimiterContext(t *testing.T) {
+	limiter := NewRateLimiter(RateLimits{
+		Group: Rate{Requests: 1, Per: time.Hour},
+	})
+	params := Params{"chat_id": "-100"}
+
+	if err := limiter.Wait(context.Background(), "sendMessage", params); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := limiter.Wait(ctx, "sendMessage", params); err != context.DeadlineExceeded {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestRateLimiterPriority(t *testing.T) {
+	limiter := NewRateLimiter(RateLimits{
+		Global: Rate{Requests: 1, Per: 50 * time.Millisecond},
+	})
+	ctx := context.Background()
+
+	// Use up the burst so the following requests have to wait.
+	if err := limiter.Wait(ctx, "sendMessage", Params{"chat_id": "1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		mu    sync.Mutex
+		order []Priority
+		wg    sync.WaitGroup
+	)
+	send := func(priority Priority, chatID string) {
+		defer wg.Done()
+		if err := limiter.Wait(WithPriority(ctx, priority), "sendMessage", Params{"chat_id": chatID}); err != nil {
+			t.Error(err)
+		}
+		mu.Lock()
+		order = append(order, priority)
+		mu.Unlock()
+	}
+
+	wg.Add(3)
+	go send(PriorityLow, "2")
+	time.Sleep(5 * time.Millisecond)
+	go send(PriorityLow, "3")
+	time.Sleep(5 * time.Millisecond)
+	go send(PriorityHigh, "4")
+	wg.Wait()
+
+	if order[0] != PriorityHigh {
+		t.Errorf("expected the high priority request to be sent first, got %v", order)
+	}
+}
+
+func TestRateLimiterPreemptedDoesNotCreateBuckets(t *testing.T) {
+	limiter := NewRateLimiter(DefaultRateLimits)
+	low := &rateWaiter{priority: PriorityLow, chat: "1"}
+	high := &rateWaiter{priority: PriorityHigh, chat: "2"}
+	limiter.waiters[low] = struct{}{}
+	limiter.waiters[high] = struct{}{}
+
+	if !limiter.preempted(low, time.Now()) {
+		t.Error("expected a waiter for a chat without a bucket to preempt lower priorities")
+	}
+	if len(limiter.chats) != 0 {
+		t.Errorf("expected no chat bucket to be created, got %d", len(limiter.chats))
+	}
+
+	limiter.chatBucket("2").take()
+	if limiter.preempted(low, time.Now()) {
+		t.Error("expected a waiter for a chat without tokens not to preempt lower priorities")
+	}
+}
+
+type countingLimiter struct {
+	calls []string
+}
+
+func (l *countingLimiter) Wait(ctx context.Context, method string, params Params) error {
+	l.calls = append(l.calls, method+":"+params["chat_id"])
+	return nil
+}
+
+func TestBotUsesLimiter(t *testing.T) {
+	bot := newLocalBot(t, func(w http.ResponseWriter, r *http.Request) {
+		writeResult(w, `{"message_id":1,"date":0,"chat":{"id":5,"type":"private"}}`)
+	})
+	limiter := &countingLimiter{}
+	bot.Limiter = limiter
+
+	if _, err := bot.Send(NewMessage(5, "hello")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bot.Send(NewDocument(5, FileBytes{Name: "a.txt", Bytes: []byte("a")})); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(limiter.calls) != 2 || limiter.calls[0] != "sendMessage:5" || limiter.calls[1] != "sendDocument:5" {
+		t.Errorf("unexpected limiter calls %v", limiter.calls)
+	}
+}
@@ -0,0 +1,166 @@
+package tgbotapi
+
+// This file declares the result type of every config, which is used by Do to
+// decode the result of a request. Methods that return True on success use
+// bool.
+
+// Configs returning a Message.
+func (MessageConfig) returns(*Message)                 {}
+func (ForwardConfig) returns(*Message)                 {}
+func (PhotoConfig) returns(*Message)                   {}
+func (AudioConfig) returns(*Message)                   {}
+func (DocumentConfig) returns(*Message)                {}
+func (StickerConfig) returns(*Message)                 {}
+func (SendStickerConfig) returns(*Message)             {}
+func (VideoConfig) returns(*Message)                   {}
+func (AnimationConfig) returns(*Message)               {}
+func (VideoNoteConfig) returns(*Message)               {}
+func (PaidMediaConfig) returns(*Message)               {}
+func (VoiceConfig) returns(*Message)                   {}
+func (LocationConfig) returns(*Message)                {}
+func (VenueConfig) returns(*Message)                   {}
+func (ContactConfig) returns(*Message)                 {}
+func (SendPollConfig) returns(*Message)                {}
+func (DiceConfig) returns(*Message)                    {}
+func (SendGameConfig) returns(*Message)                {}
+func (InvoiceConfig) returns(*Message)                 {}
+func (SendChecklistConfig) returns(*Message)           {}
+func (EditMessageLiveLocationConfig) returns(*Message) {}
+func (StopMessageLiveLocationConfig) returns(*Message) {}
+func (EditMessageTextConfig) returns(*Message)         {}
+func (EditMessageCaptionConfig) returns(*Message)      {}
+func (EditMessageMediaConfig) returns(*Message)        {}
+func (EditMessageReplyMarkupConfig) returns(*Message)  {}
+func (EditMessageChecklistConfig) returns(*Message)    {}
+func (SetGameScoreConfig) returns(*Message)            {}
+
+// Configs returning other types.
+func (ForwardMsgsConfig) returns(*[]MessageID)                                 {}
+func (CopyMessageConfig) returns(*MessageID)                                   {}
+func (CopyMessagesConfig) returns(*[]MessageID)                                {}
+func (MediaGroupConfig) returns(*[]Message)                                    {}
+func (StopPollConfig) returns(*Poll)                                           {}
+func (UserProfilePhotosConfig) returns(*UserProfilePhotos)                     {}
+func (FileConfig) returns(*File)                                               {}
+func (UpdateConfig) returns(*[]Update)                                         {}
+func (WebhookInfo) returns(*WebhookInfo)                                       {}
+func (AnswerWebAppQueryConfig) returns(*SentWebAppMessage)                     {}
+func (ChatInfoConfig) returns(*Chat)                                           {}
+func (ChatMemberCountConfig) returns(*int)                                     {}
+func (ChatAdministratorsConfig) returns(*[]ChatMember)                         {}
+func (GetChatMemberConfig) returns(*ChatMember)                                {}
+func (ChatInviteLinkConfig) returns(*string)                                   {}
+func (CreateChatInviteLinkConfig) returns(*ChatInviteLink)                     {}
+func (EditChatInviteLinkConfig) returns(*ChatInviteLink)                       {}
+func (RevokeChatInviteLinkConfig) returns(*ChatInviteLink)                     {}
+func (ChatSubscriptionInviteLinkConfig) returns(*ChatInviteLink)               {}
+func (EditChatSubscriptionInviteLinkConfig) returns(*ChatInviteLink)           {}
+func (ForumTopicIconStickersConfig) returns(*[]Sticker)                        {}
+func (ForumTopicConfig) returns(*ForumTopicInfo)                               {}
+func (UserChatBoostsConfig) returns(*UserChatBoosts)                           {}
+func (BusinessConnectionConfig) returns(*BusinessConnection)                   {}
+func (GetMyNameConfig) returns(*BotName)                                       {}
+func (GetMyDescriptionConfig) returns(*BotDescription)                         {}
+func (GetMyShortDescriptionConfig) returns(*BotShortDescription)               {}
+func (GetMyCommandsConfig) returns(*[]BotCommand)                              {}
+func (GetChatMenuButtonConfig) returns(*MenuButton)                            {}
+func (GetMyDefaultAdministratorRightsConfig) returns(*ChatAdministratorRights) {}
+func (GetAvailableGiftsConfig) returns(*Gifts)                                 {}
+func (GetBusinessAccountStarBalanceConfig) returns(*StarAmount)                {}
+func (GetBusinessAccountGiftsConfig) returns(*OwnedGifts)                      {}
+func (GetGameHighScoresConfig) returns(*[]GameHighScore)                       {}
+func (SavePreparedInlineMessageConfig) returns(*PreparedInlineMessage)         {}
+func (InvoiceLinkConfig) returns(*string)                                      {}
+func (GetStarTransactionsConfig) returns(*StarTransactions)                    {}
+func (GetMyStarBalanceConfig) returns(*StarAmount)                             {}
+func (GetStickerSetConfig) returns(*StickerSet)                                {}
+func (GetCustomEmojiStickersConfig) returns(*[]Sticker)                        {}
+func (UploadStickerFileConfig) returns(*File)                                  {}
+func (PostStoryConfig) returns(*Story)                                         {}
+func (EditStoryConfig) returns(*Story)                                         {}
+
+// Configs returning True on success.
+func (LogOutConfig) returns(*bool)                            {}
+func (CloseConfig) returns(*bool)                             {}
+func (DeleteMessagesConfig) returns(*bool)                    {}
+func (SendGiftConfig) returns(*bool)                          {}
+func (GiftPremiumSubscriptionConfig) returns(*bool)           {}
+func (VerifyUserConfig) returns(*bool)                        {}
+func (VerifyChatConfig) returns(*bool)                        {}
+func (RemoveUserVerificationConfig) returns(*bool)            {}
+func (RemoveChatVerificationConfig) returns(*bool)            {}
+func (MessageReactionConfig) returns(*bool)                   {}
+func (UserEmojiStatusConfig) returns(*bool)                   {}
+func (ChatActionConfig) returns(*bool)                        {}
+func (WebhookConfig) returns(*bool)                           {}
+func (DeleteWebhookConfig) returns(*bool)                     {}
+func (InlineConfig) returns(*bool)                            {}
+func (CallbackConfig) returns(*bool)                          {}
+func (UnbanChatMemberConfig) returns(*bool)                   {}
+func (BanChatMemberConfig) returns(*bool)                     {}
+func (RestrictChatMemberConfig) returns(*bool)                {}
+func (PromoteChatMemberConfig) returns(*bool)                 {}
+func (SetChatAdministratorCustomTitle) returns(*bool)         {}
+func (BanChatSenderChatConfig) returns(*bool)                 {}
+func (UnbanChatSenderChatConfig) returns(*bool)               {}
+func (SetChatPermissionsConfig) returns(*bool)                {}
+func (ApproveChatJoinRequestConfig) returns(*bool)            {}
+func (DeclineChatJoinRequest) returns(*bool)                  {}
+func (LeaveChatConfig) returns(*bool)                         {}
+func (ShippingConfig) returns(*bool)                          {}
+func (PreCheckoutConfig) returns(*bool)                       {}
+func (DeleteMessageConfig) returns(*bool)                     {}
+func (PinChatMessageConfig) returns(*bool)                    {}
+func (UnpinChatMessageConfig) returns(*bool)                  {}
+func (UnpinAllChatMessagesConfig) returns(*bool)              {}
+func (EditForumTopicConfig) returns(*bool)                    {}
+func (CloseForumTopicConfig) returns(*bool)                   {}
+func (ReopenForumTopicConfig) returns(*bool)                  {}
+func (DeleteForumTopicConfig) returns(*bool)                  {}
+func (UnpinAllForumTopicMessagesConfig) returns(*bool)        {}
+func (EditGeneralForumTopicConfig) returns(*bool)             {}
+func (CloseGeneralForumTopicConfig) returns(*bool)            {}
+func (ReopenGeneralForumTopicConfig) returns(*bool)           {}
+func (HideGeneralForumTopicConfig) returns(*bool)             {}
+func (UnhideGeneralForumTopicConfig) returns(*bool)           {}
+func (UnpinAllGeneralForumTopicMessagesConfig) returns(*bool) {}
+func (SetMyNameConfig) returns(*bool)                         {}
+func (SetMyDescriptionConfig) returns(*bool)                  {}
+func (SetMyShortDescriptionConfig) returns(*bool)             {}
+func (SetChatPhotoConfig) returns(*bool)                      {}
+func (DeleteChatPhotoConfig) returns(*bool)                   {}
+func (SetChatTitleConfig) returns(*bool)                      {}
+func (SetChatDescriptionConfig) returns(*bool)                {}
+func (SetMyCommandsConfig) returns(*bool)                     {}
+func (DeleteMyCommandsConfig) returns(*bool)                  {}
+func (SetChatMenuButtonConfig) returns(*bool)                 {}
+func (SetMyDefaultAdministratorRightsConfig) returns(*bool)   {}
+func (ConvertGiftToStarsConfig) returns(*bool)                {}
+func (UpgradeGiftConfig) returns(*bool)                       {}
+func (TransferGiftConfig) returns(*bool)                      {}
+func (ReadBusinessMessageConfig) returns(*bool)               {}
+func (DeleteBusinessMessagesConfig) returns(*bool)            {}
+func (SetBusinessAccountNameConfig) returns(*bool)            {}
+func (SetBusinessAccountUsernameConfig) returns(*bool)        {}
+func (SetBusinessAccountBioConfig) returns(*bool)             {}
+func (RemoveBusinessAccountProfilePhotoConfig) returns(*bool) {}
+func (SetBusinessAccountGiftSettingsConfig) returns(*bool)    {}
+func (TransferBusinessAccountStarsConfig) returns(*bool)      {}
+func (SetPassportDataErrorsConfig) returns(*bool)             {}
+func (RefundStarPaymentConfig) returns(*bool)                 {}
+func (EditUserStarSubscriptionConfig) returns(*bool)          {}
+func (CreateNewStickerSetConfig) returns(*bool)               {}
+func (AddStickerToSetConfig) returns(*bool)                   {}
+func (SetStickerPositionInSetConfig) returns(*bool)           {}
+func (DeleteStickerFromSetConfig) returns(*bool)              {}
+func (ReplaceStickerInSetConfig) returns(*bool)               {}
+func (SetStickerEmojiListConfig) returns(*bool)               {}
+func (SetStickerKeywordsConfig) returns(*bool)                {}
+func (SetStickerMaskPositionConfig) returns(*bool)            {}
+func (SetStickerSetTitleConfig) returns(*bool)                {}
+func (SetStickerSetThumbnailConfig) returns(*bool)            {}
+func (SetCustomEmojiStickerSetThumbnailConfig) returns(*bool) {}
+func (DeleteStickerSetConfig) returns(*bool)                  {}
+func (SetChatStickerSetConfig) returns(*bool)                 {}
+func (DeleteChatStickerSetConfig) returns(*bool)              {}
+func (DeleteStoryConfig) returns(*bool)                       {}
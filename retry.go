@@ -0,0 +1,176 @@
+package tgbotapi
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"strings"
+	"time"
+)
+
+// RetryPolicy describes how failed requests are retried.
+//
+// Requests rejected by flood control (HTTP 429) are always safe to repeat, as
+// Telegram did not process them, so they are retried for every method after
+// waiting for the RetryAfter period returned by Telegram.
+//
+// Server errors (5xx) and transport errors are ambiguous: the request may have
+// been processed before the failure. These are only retried for methods that
+// are safe to repeat, such as getUpdates or getChat, using exponential backoff
+// with jitter. Methods that send or change something, such as sendMessage,
+// must be opted in through Methods to avoid duplicates.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts made for a request,
+	// including the first one.
+	MaxAttempts int
+	// MaxElapsed limits the total time spent on a request, including waiting
+	// between attempts. Zero means no limit.
+	MaxElapsed time.Duration
+	// BaseDelay is the delay before the first retry of a server or transport
+	// error. It doubles for every following attempt.
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between two attempts. Requests Telegram asks to
+	// retry after a longer RetryAfter period fail with the flood control
+	// error instead of being retried early. Zero means no limit.
+	MaxDelay time.Duration
+	// Methods overrides whether a method may be retried after a server or
+	// transport error. A true value opts a method in, a false value opts it
+	// out. Methods not listed use the built-in list of safe methods.
+	Methods map[string]bool
+}
+
+// NewRetryPolicy returns a RetryPolicy with reasonable defaults.
+func NewRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 5,
+		MaxElapsed:  2 * time.Minute,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    time.Minute,
+	}
+}
+
+// safeMethods are methods without a get prefix that can be repeated without
+// changing the outcome.
+var safeMethods = map[string]bool{
+	"setWebhook":                      true,
+	"deleteWebhook":                   true,
+	"setMyCommands":                   true,
+	"deleteMyCommands":                true,
+	"setMyName":                       true,
+	"setMyDescription":                true,
+	"setMyShortDescription":           true,
+	"setChatMenuButton":               true,
+	"setMyDefaultAdministratorRights": true,
+	"setChatTitle":                    true,
+	"setChatDescription":              true,
+	"setChatPermissions":              true,
+}
+
+// IsSafeToRetry returns true if method may be retried after a server or
+// transport error. All methods may be retried after flood control errors.
+func (p *RetryPolicy) IsSafeToRetry(method string) bool {
+	if allowed, ok := p.Methods[method]; ok {
+		return allowed
+	}
+
+	return strings.HasPrefix(method, "get") || safeMethods[method]
+}
+
+// delay returns how long to wait before making the given attempt after err.
+// It returns false if the request should not be retried.
+func (p *RetryPolicy) delay(method string, attempt int, err error) (time.Duration, bool) {
+	if !IsRetryable(err) {
+		return 0, false
+	}
+
+	var apiErr *Error
+	if errors.As(err, &apiErr) && (apiErr.Is(ErrTooManyRequests) || apiErr.RetryAfter > 0) {
+		if apiErr.RetryAfter > 0 {
+			// Retrying before RetryAfter would hit flood control again.
+			retryAfter := time.Duration(apiErr.RetryAfter) * time.Second
+			return retryAfter, p.MaxDelay == 0 || retryAfter <= p.MaxDelay
+		}
+
+		return p.backoff(attempt), true
+	}
+
+	if !p.IsSafeToRetry(method) {
+		return 0, false
+	}
+
+	return p.backoff(attempt), true
+}
+
+// backoff returns an exponentially growing delay with jitter for the given
+// attempt, where attempt 1 is the first retry.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay == 0 || d < p.MaxDelay); i++ {
+		d *= 2
+	}
+	d = p.limit(d)
+
+	if d <= 0 {
+		return 0
+	}
+
+	// Wait at least half of the delay so retries still back off, with the
+	// other half randomised to spread out clients retrying at once.
+	half := d / 2
+	return half + rand.N(d-half+1)
+}
+
+func (p *RetryPolicy) limit(d time.Duration) time.Duration {
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		return p.MaxDelay
+	}
+
+	return d
+}
+
+// withRetry calls do until it succeeds or the bot's RetryPolicy says the
+// request should not be retried anymore.
+func (bot *BotAPI) withRetry(ctx context.Context, method string, do func(ctx context.Context) (*APIResponse, error)) (*APIResponse, error) {
+	policy := bot.Retry
+	if policy == nil {
+		return do(ctx)
+	}
+
+	start := time.Now()
+
+	for attempt := 1; ; attempt++ {
+		resp, err := do(ctx)
+		if err == nil || attempt >= policy.MaxAttempts || ctx.Err() != nil {
+			return resp, err
+		}
+
+		delay, ok := policy.delay(method, attempt, err)
+		if !ok {
+			return resp, err
+		}
+
+		if policy.MaxElapsed > 0 && time.Since(start)+delay > policy.MaxElapsed {
+			return resp, err
+		}
+
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return resp, err
+		}
+
+		bot.log(ctx, slog.LevelDebug, "retrying request",
+			slog.String("method", method),
+			slog.Int("attempt", attempt),
+			slog.Duration("delay", delay),
+			slog.String("error", err.Error()),
+		)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return resp, err
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package tgbotapi
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryFloodWait(t *testing.T) {
+	var calls int32
+	bot := newLocalBot(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = io.WriteString(w, `{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 1","parameters":{"retry_after":1}}`)
+			return
+		}
+		writeResult(w, `{"message_id":1,"date":0,"chat":{"id":1,"type":"private"}}`)
+	})
+	bot.Retry = NewRetryPolicy()
+
+	start := time.Now()
+	msg, err := bot.Send(NewMessage(1, "hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.MessageID != 1 {
+		t.Errorf("unexpected message %+v", msg)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls, got %d", calls)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retry did not wait for retry_after, waited %s", elapsed)
+	}
+}
+
+func TestRetryFloodWaitLongerThanMaxDelay(t *testing.T) {
+	var calls int32
+	bot := newLocalBot(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = io.WriteString(w, `{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 120","parameters":{"retry_after":120}}`)
+	})
+	bot.Retry = NewRetryPolicy()
+
+	start := time.Now()
+	_, err := bot.Send(NewMessage(1, "hello"))
+
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.RetryAfter != 120 {
+		t.Fatalf("expected the flood control error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected no retry before retry_after, got %d calls", calls)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the error to be returned right away, waited %s", elapsed)
+	}
+}
+
+func TestRetryServerError(t *testing.T) {
+	var calls int32
+	bot := newLocalBot(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = io.WriteString(w, "<html>Bad Gateway</html>")
+	})
+	bot.Retry = &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+
+	_, err := bot.GetChat(ChatInfoConfig{ChatConfig{ChatID: 1}})
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.Code != http.StatusBadGateway {
+		t.Fatalf("expected a 502 Error, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected getChat to be attempted 3 times, got %d", calls)
+	}
+
+	atomic.StoreInt32(&calls, 0)
+	_, err = bot.Send(NewMessage(1, "hello"))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if calls != 1 {
+		t.Errorf("expected sendMessage to be attempted once, got %d", calls)
+	}
+
+	atomic.StoreInt32(&calls, 0)
+	bot.Retry.Methods = map[string]bool{"sendMessage": true}
+	_, _ = bot.Send(NewMessage(1, "hello"))
+	if calls != 3 {
+		t.Errorf("expected opted in sendMessage to be attempted 3 times, got %d", calls)
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := &RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	for attempt := 1; attempt < 10; attempt++ {
+		d := p.backoff(attempt)
+		if d > time.Second {
+			t.Errorf("attempt %d: delay %s exceeds MaxDelay", attempt, d)
+		}
+		if d < 50*time.Millisecond {
+			t.Errorf("attempt %d: delay %s is shorter than half of BaseDelay", attempt, d)
+		}
+	}
+}
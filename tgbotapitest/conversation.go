@@ -0,0 +1,378 @@
+package tgbotapitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf16"
+
+	tgbotapi "github.com/telemmx/telegram-bot-api/v9"
+)
+
+// DefaultTimeout is how long a Conversation waits for the bot to make a call
+// by default.
+const DefaultTimeout = 2 * time.Second
+
+// Conversation simulates users talking to a bot using a Server. Users send
+// messages, click buttons and share contacts, which become updates delivered
+// to the bot, and the test waits for the calls the bot makes in response:
+//
+//	conv := tgbotapitest.NewConversation(t, srv)
+//	user := conv.User(42)
+//
+//	user.Send("/start")
+//	menu := conv.ExpectMessage(user.ID)
+//
+//	query := user.Click(menu, "buy:7")
+//	conv.ExpectAnswer(query)
+//	conv.ExpectEdit(user.ID)
+//
+// Messages sent by users and by the bot share the message IDs of their chat,
+// so replies, edits and clicks refer to the messages the server knows.
+//
+// Failed expectations end the test with t.Fatal.
+type Conversation struct {
+	// Server is the server used by the bot.
+	Server *Server
+	// Timeout is how long to wait for the bot to make an expected call. It
+	// defaults to DefaultTimeout.
+	Timeout time.Duration
+
+	t       testing.TB
+	webhook http.Handler
+
+	mu           sync.Mutex
+	consumed     map[int]bool
+	nextUpdateID int
+	nextQuery    int
+}
+
+// NewConversation creates a conversation adding updates to srv, to be
+// received by the bot with GetUpdatesChan.
+func NewConversation(t testing.TB, srv *Server) *Conversation {
+	return &Conversation{
+		Server:   srv,
+		Timeout:  DefaultTimeout,
+		t:        t,
+		consumed: make(map[int]bool),
+	}
+}
+
+// NewWebhookConversation creates a conversation posting updates to handler,
+// as Telegram does for webhooks. Updates are delivered once handler returns.
+func NewWebhookConversation(t testing.TB, srv *Server, handler http.Handler) *Conversation {
+	c := NewConversation(t, srv)
+	c.webhook = handler
+	c.nextUpdateID = 1
+
+	return c
+}
+
+// User returns a user with the given ID, talking to the bot in their private
+// chat.
+func (c *Conversation) User(id int64) *User {
+	name := "User " + strconv.FormatInt(id, 10)
+
+	return &User{
+		User: tgbotapi.User{ID: id, FirstName: name, LanguageCode: "en"},
+		Chat: tgbotapi.Chat{ID: id, Type: "private", FirstName: name},
+		conv: c,
+	}
+}
+
+// Group returns a group chat with the given ID and title. Group IDs are
+// negative, and IDs lower than -1000000000000 are supergroups.
+func (c *Conversation) Group(id int64, title string) tgbotapi.Chat {
+	chat := tgbotapi.Chat{ID: id, Type: "group", Title: title}
+	if id < -1000000000000 {
+		chat.Type = "supergroup"
+	}
+
+	return chat
+}
+
+// Deliver delivers an update to the bot. Updates without an UpdateID are
+// given one.
+func (c *Conversation) Deliver(update tgbotapi.Update) {
+	c.t.Helper()
+
+	if c.webhook == nil {
+		c.Server.AddUpdates(update)
+		return
+	}
+
+	c.mu.Lock()
+	if update.UpdateID == 0 {
+		update.UpdateID = c.nextUpdateID
+	}
+	c.nextUpdateID = max(c.nextUpdateID, update.UpdateID+1)
+	c.mu.Unlock()
+
+	data, err := json.Marshal(update)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	c.webhook.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(data)))
+
+	if w.Code >= http.StatusMultipleChoices {
+		c.t.Fatalf("webhook returned %d: %s", w.Code, w.Body)
+	}
+}
+
+// Expect waits for the next call to method, and returns it.
+func (c *Conversation) Expect(method string) Call {
+	c.t.Helper()
+
+	return c.expect(method, func(call Call) bool {
+		return call.Method == method
+	})
+}
+
+// ExpectMessage waits for the bot to send the next message to chatID, with
+// any method sending a message, and returns the message sent.
+func (c *Conversation) ExpectMessage(chatID int64) tgbotapi.Message {
+	c.t.Helper()
+
+	call := c.expect(fmt.Sprintf("a message to %d", chatID), func(call Call) bool {
+		_, ok := call.Result.(tgbotapi.Message)
+		return ok && call.Int64("chat_id") == chatID && !strings.HasPrefix(call.Method, "edit")
+	})
+
+	return call.Result.(tgbotapi.Message)
+}
+
+// ExpectEdit waits for the bot to edit the next message in chatID, and returns
+// the edited message.
+func (c *Conversation) ExpectEdit(chatID int64) tgbotapi.Message {
+	c.t.Helper()
+
+	call := c.expect(fmt.Sprintf("an edit in %d", chatID), func(call Call) bool {
+		_, ok := call.Result.(tgbotapi.Message)
+		return ok && call.Int64("chat_id") == chatID && strings.HasPrefix(call.Method, "edit")
+	})
+
+	return call.Result.(tgbotapi.Message)
+}
+
+// ExpectAnswer waits for the bot to answer query with answerCallbackQuery,
+// and returns the call.
+func (c *Conversation) ExpectAnswer(query tgbotapi.CallbackQuery) Call {
+	c.t.Helper()
+
+	return c.expect("an answer to callback query "+query.ID, func(call Call) bool {
+		return call.Method == "answerCallbackQuery" && call.Params["callback_query_id"] == query.ID
+	})
+}
+
+// Unexpected returns the calls the bot made that weren't returned by an
+// expectation, other than getUpdates, getMe and webhook management, so tests
+// can check the bot didn't do more than expected.
+func (c *Conversation) Unexpected() []Call {
+	calls, _ := c.Server.completedCalls()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var unexpected []Call
+	for _, call := range calls {
+		switch call.Method {
+		case "getUpdates", "getMe", "setWebhook", "deleteWebhook", "getWebhookInfo":
+			continue
+		}
+		if !c.consumed[call.seq] {
+			unexpected = append(unexpected, call)
+		}
+	}
+
+	return unexpected
+}
+
+// expect waits for the first call matching match that wasn't returned yet,
+// and returns it.
+func (c *Conversation) expect(description string, match func(Call) bool) Call {
+	c.t.Helper()
+
+	timeout := time.NewTimer(c.Timeout)
+	defer timeout.Stop()
+
+	for {
+		calls, done := c.Server.completedCalls()
+
+		c.mu.Lock()
+		for _, call := range calls {
+			if !c.consumed[call.seq] && match(call) {
+				c.consumed[call.seq] = true
+				c.mu.Unlock()
+				return call
+			}
+		}
+		c.mu.Unlock()
+
+		select {
+		case <-done:
+		case <-timeout.C:
+			c.t.Fatalf("expected %s after %s, got calls %v", description, c.Timeout, methods(calls))
+			return Call{}
+		}
+	}
+}
+
+// methods returns the methods of calls, for failure messages.
+func methods(calls []Call) []string {
+	names := make([]string, 0, len(calls))
+	for _, call := range calls {
+		names = append(names, call.Method)
+	}
+
+	return names
+}
+
+// User is a user talking to the bot in a Conversation.
+type User struct {
+	tgbotapi.User
+	// Chat is the chat the user sends messages to.
+	Chat tgbotapi.Chat
+
+	conv *Conversation
+}
+
+// In returns the same user sending messages to chat, such as a group created
+// with Conversation.Group.
+func (u *User) In(chat tgbotapi.Chat) *User {
+	in := *u
+	in.Chat = chat
+
+	return &in
+}
+
+// Send sends a text message, and returns it. Messages starting with a slash
+// are sent as commands.
+func (u *User) Send(text string) tgbotapi.Message {
+	u.conv.t.Helper()
+
+	return u.SendMessage(tgbotapi.Message{Text: text})
+}
+
+// Reply sends a text message replying to message, and returns it.
+func (u *User) Reply(message tgbotapi.Message, text string) tgbotapi.Message {
+	u.conv.t.Helper()
+
+	message.ReplyToMessage = nil
+
+	return u.SendMessage(tgbotapi.Message{Text: text, ReplyToMessage: &message})
+}
+
+// ShareContact sends the user's own contact with the given phone number, as
+// a reply keyboard button requesting the contact does, and returns the
+// message.
+func (u *User) ShareContact(phoneNumber string) tgbotapi.Message {
+	u.conv.t.Helper()
+
+	return u.SendMessage(tgbotapi.Message{
+		Contact: &tgbotapi.Contact{
+			PhoneNumber: phoneNumber,
+			FirstName:   u.FirstName,
+			LastName:    u.LastName,
+			UserID:      u.ID,
+		},
+	})
+}
+
+// SendMessage sends message from the user to their chat, and returns it. The
+// message is given an ID, a date, its sender and chat, and a bot_command
+// entity if its text starts with a slash.
+func (u *User) SendMessage(message tgbotapi.Message) tgbotapi.Message {
+	u.conv.t.Helper()
+
+	from, chat := u.User, u.Chat
+	message.From = &from
+	message.Chat = &chat
+	message.MessageID = 0
+
+	if strings.HasPrefix(message.Text, "/") && len(message.Entities) == 0 {
+		command, _, _ := strings.Cut(message.Text, " ")
+		message.Entities = []tgbotapi.MessageEntity{{
+			Type:   "bot_command",
+			Offset: 0,
+			Length: len(utf16.Encode([]rune(command))),
+		}}
+	}
+
+	message = u.conv.Server.StoreMessage(message)
+	u.conv.Deliver(tgbotapi.Update{Message: &message})
+
+	return message
+}
+
+// Click clicks the inline keyboard button of message with the callback data,
+// and returns the callback query sent to the bot. The button is looked up in
+// the current version of the message on the server, so clicks see the edits
+// made by the bot.
+func (u *User) Click(message tgbotapi.Message, data string) tgbotapi.CallbackQuery {
+	u.conv.t.Helper()
+
+	return u.click(message, "data "+strconv.Quote(data), func(button tgbotapi.InlineKeyboardButton) bool {
+		return button.CallbackData != nil && *button.CallbackData == data
+	})
+}
+
+// ClickText clicks the inline keyboard button of message with the given text,
+// like Click.
+func (u *User) ClickText(message tgbotapi.Message, text string) tgbotapi.CallbackQuery {
+	u.conv.t.Helper()
+
+	return u.click(message, "text "+strconv.Quote(text), func(button tgbotapi.InlineKeyboardButton) bool {
+		return button.Text == text && button.CallbackData != nil
+	})
+}
+
+// click sends the callback query of the first button of message matching
+// match.
+func (u *User) click(message tgbotapi.Message, description string, match func(tgbotapi.InlineKeyboardButton) bool) tgbotapi.CallbackQuery {
+	u.conv.t.Helper()
+
+	current, ok := u.conv.Server.Message(message.Chat.ID, message.MessageID)
+	if !ok {
+		u.conv.t.Fatalf("message %d in chat %d doesn't exist", message.MessageID, message.Chat.ID)
+	}
+	if current.ReplyMarkup == nil {
+		u.conv.t.Fatalf("message %d in chat %d has no inline keyboard", message.MessageID, message.Chat.ID)
+	}
+
+	for _, row := range current.ReplyMarkup.InlineKeyboard {
+		for _, button := range row {
+			if !match(button) {
+				continue
+			}
+
+			u.conv.mu.Lock()
+			u.conv.nextQuery++
+			id := strconv.Itoa(u.conv.nextQuery)
+			u.conv.mu.Unlock()
+
+			from := u.User
+			query := tgbotapi.CallbackQuery{
+				ID:           id,
+				From:         &from,
+				Message:      &current,
+				ChatInstance: strconv.FormatInt(current.Chat.ID, 10),
+				Data:         *button.CallbackData,
+			}
+			u.conv.Deliver(tgbotapi.Update{CallbackQuery: &query})
+
+			return query
+		}
+	}
+
+	u.conv.t.Fatalf("message %d in chat %d has no button with %s", message.MessageID, message.Chat.ID, description)
+	return tgbotapi.CallbackQuery{}
+}
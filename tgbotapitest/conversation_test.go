@@ -0,0 +1,103 @@
+package tgbotapitest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	tgbotapi "github.com/telemmx/telegram-bot-api/v9"
+)
+
+// shopBot handles updates the way a small bot would.
+func shopBot(t *testing.T, bot *tgbotapi.BotAPI, update tgbotapi.Update) {
+	switch {
+	case update.Message != nil && update.Message.Command() == "start":
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "What do you want?")
+		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData("Apple", "buy:7")),
+		)
+		if _, err := bot.Send(msg); err != nil {
+			t.Error(err)
+		}
+	case update.Message != nil && update.Message.Contact != nil:
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Thanks, "+update.Message.Contact.FirstName)
+		msg.ReplyParameters = &tgbotapi.ReplyParameters{MessageId: update.Message.MessageID}
+		if _, err := bot.Send(msg); err != nil {
+			t.Error(err)
+		}
+	case update.CallbackQuery != nil:
+		query := update.CallbackQuery
+		if _, err := bot.Request(tgbotapi.NewCallback(query.ID, "Bought "+query.Data)); err != nil {
+			t.Error(err)
+		}
+		edit := tgbotapi.NewEditMessageText(query.Message.Chat.ID, query.Message.MessageID, "Done")
+		if _, err := bot.Send(edit); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func testConversation(t *testing.T, conv *Conversation) {
+	user := conv.User(42)
+
+	start := user.Send("/start")
+	menu := conv.ExpectMessage(user.ID)
+	if menu.Text != "What do you want?" || menu.MessageID != start.MessageID+1 {
+		t.Errorf("unexpected menu %+v after message %d", menu, start.MessageID)
+	}
+
+	query := user.Click(menu, "buy:7")
+	if answer := conv.ExpectAnswer(query); answer.Params["text"] != "Bought buy:7" {
+		t.Errorf("unexpected answer %+v", answer.Params)
+	}
+	if edited := conv.ExpectEdit(user.ID); edited.MessageID != menu.MessageID || edited.Text != "Done" {
+		t.Errorf("unexpected edit %+v", edited)
+	}
+
+	contact := user.ShareContact("+15551234567")
+	reply := conv.ExpectMessage(user.ID)
+	if reply.Text != "Thanks, User 42" || reply.ReplyToMessage == nil || reply.ReplyToMessage.MessageID != contact.MessageID {
+		t.Errorf("unexpected reply %+v", reply)
+	}
+
+	group := user.In(conv.Group(-100, "Friends"))
+	group.Send("/start@test_bot")
+	if msg := conv.ExpectMessage(-100); msg.Chat.Type != "group" {
+		t.Errorf("expected a message in the group, got %+v", msg.Chat)
+	}
+
+	if unexpected := conv.Unexpected(); len(unexpected) != 0 {
+		t.Errorf("unexpected calls %v", methods(unexpected))
+	}
+}
+
+func TestConversationPolling(t *testing.T) {
+	srv, bot := newBot(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	updates := bot.GetUpdatesChanWithContext(ctx, tgbotapi.UpdateConfig{Timeout: 60})
+	go func() {
+		for update := range updates {
+			shopBot(t, bot, update)
+		}
+	}()
+
+	testConversation(t, NewConversation(t, srv))
+}
+
+func TestConversationWebhook(t *testing.T) {
+	srv, bot := newBot(t)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		update, err := bot.HandleUpdate(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		shopBot(t, bot, *update)
+	})
+
+	testConversation(t, NewWebhookConversation(t, srv, handler))
+}
@@ -0,0 +1,83 @@
+package tgbotapitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	tgbotapi "github.com/telemmx/telegram-bot-api/v9"
+)
+
+// Failure is an error returned by the server, with the error code and
+// description Telegram would send.
+type Failure struct {
+	// Code is the error code, also used as HTTP status.
+	Code int
+	// Description is the human-readable description of the error.
+	Description string
+	// RetryAfter is the number of seconds to wait before retrying, for flood
+	// control errors.
+	RetryAfter int
+	// MigrateToChatID is the ID of the supergroup a group was migrated to.
+	MigrateToChatID int64
+}
+
+func (f Failure) Error() string {
+	return f.Description
+}
+
+// BadRequest returns a failure with code 400, such as
+// BadRequest("message is not modified").
+func BadRequest(description string) Failure {
+	return Failure{Code: http.StatusBadRequest, Description: "Bad Request: " + description}
+}
+
+// Forbidden returns a failure with code 403, such as
+// Forbidden("bot was kicked from the group chat").
+func Forbidden(description string) Failure {
+	return Failure{Code: http.StatusForbidden, Description: "Forbidden: " + description}
+}
+
+// BotBlocked returns the failure for requests to a user who blocked the bot.
+func BotBlocked() Failure {
+	return Forbidden("bot was blocked by the user")
+}
+
+// TooManyRequests returns the failure for requests rejected by flood control,
+// asking to wait for retryAfter seconds.
+func TooManyRequests(retryAfter int) Failure {
+	return Failure{
+		Code:        http.StatusTooManyRequests,
+		Description: fmt.Sprintf("Too Many Requests: retry after %d", retryAfter),
+		RetryAfter:  retryAfter,
+	}
+}
+
+// ChatMigrated returns the failure for requests to a group that was upgraded
+// to the supergroup with the ID to.
+func ChatMigrated(to int64) Failure {
+	failure := BadRequest("group chat was upgraded to a supergroup chat")
+	failure.MigrateToChatID = to
+
+	return failure
+}
+
+// writeFailure writes the response Telegram sends for failure.
+func writeFailure(w http.ResponseWriter, failure Failure) {
+	resp := tgbotapi.APIResponse{
+		Ok:          false,
+		ErrorCode:   failure.Code,
+		Description: failure.Description,
+	}
+
+	if failure.RetryAfter != 0 || failure.MigrateToChatID != 0 {
+		resp.Parameters = &tgbotapi.ResponseParameters{
+			RetryAfter:      failure.RetryAfter,
+			MigrateToChatID: failure.MigrateToChatID,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(failure.Code)
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,355 @@
+package tgbotapitest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+
+	tgbotapi "github.com/telemmx/telegram-bot-api/v9"
+)
+
+// Mode is how a Recorder handles requests.
+type Mode int
+
+const (
+	// ModeReplay answers requests with the responses in the cassette, and
+	// fails requests that weren't recorded.
+	ModeReplay Mode = iota
+	// ModeRecord makes requests with the recorder's client and records them
+	// in the cassette, which must be saved with Save.
+	ModeRecord
+	// ModeAuto replays the cassette if its file exists, and records it
+	// otherwise.
+	ModeAuto
+)
+
+// ScrubbedToken replaces bot tokens in cassettes.
+const ScrubbedToken = "BOT_TOKEN"
+
+// Recorder is a tgbotapi.HTTPClient that records requests made to Telegram
+// and their responses in a cassette file, and replays them later, so tests
+// can run offline against responses from a real bot:
+//
+//	rec, err := tgbotapitest.NewRecorder("testdata/send.json", tgbotapitest.ModeAuto)
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	defer rec.Save()
+//
+//	bot, err := tgbotapi.NewBotAPIWithClient(token, tgbotapi.APIEndpoint, rec)
+//
+// Requests are matched on their method, params and uploaded files, in the
+// order they were recorded. The encoding of the body doesn't matter, so
+// multipart boundaries and the order of JSON object keys are ignored. Tokens
+// are replaced by ScrubbedToken in cassettes, and in requests before they are
+// matched, so cassettes can be replayed with any token.
+//
+// It is safe for concurrent use.
+type Recorder struct {
+	// Client makes the requests being recorded. It defaults to
+	// http.DefaultClient.
+	Client tgbotapi.HTTPClient
+
+	path string
+	mode Mode
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// Cassette is the content of a cassette file.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a request recorded in a cassette, with its response.
+type Interaction struct {
+	// Method is the Bot API method called, or the path of a downloaded
+	// file prefixed by "file/".
+	Method string `json:"method"`
+	// Params are the normalized params of the request.
+	Params map[string]string `json:"params,omitempty"`
+	// Files are the files uploaded, by field name, as their file name and
+	// the SHA-256 hash of their content.
+	Files map[string]string `json:"files,omitempty"`
+	// Response is the response returned by Telegram.
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedResponse is a response recorded in a cassette.
+type RecordedResponse struct {
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type,omitempty"`
+	// Body is the body of JSON responses.
+	Body json.RawMessage `json:"body,omitempty"`
+	// Data is the body of other responses, such as downloaded files.
+	Data []byte `json:"data,omitempty"`
+}
+
+// NewRecorder creates a Recorder using the cassette at path. In ModeAuto, the
+// mode is resolved to ModeReplay or ModeRecord depending on the existence of
+// the file.
+func NewRecorder(path string, mode Mode) (*Recorder, error) {
+	rec := &Recorder{Client: http.DefaultClient, path: path, mode: mode}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && mode != ModeReplay {
+		rec.mode = ModeRecord
+		return rec, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if mode == ModeAuto {
+		rec.mode = ModeReplay
+	}
+
+	if rec.mode == ModeReplay {
+		if err := json.Unmarshal(data, &rec.cassette); err != nil {
+			return nil, fmt.Errorf("reading cassette %s: %w", path, err)
+		}
+		rec.used = make([]bool, len(rec.cassette.Interactions))
+	}
+
+	return rec, nil
+}
+
+// Mode returns whether the recorder replays or records requests.
+func (rec *Recorder) Mode() Mode {
+	return rec.mode
+}
+
+// Do implements tgbotapi.HTTPClient.
+func (rec *Recorder) Do(req *http.Request) (*http.Response, error) {
+	interaction, body, err := newInteraction(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if rec.mode == ModeReplay {
+		return rec.replay(req, interaction)
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+
+	resp, err := rec.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	interaction.Response = RecordedResponse{
+		Status:      resp.StatusCode,
+		ContentType: resp.Header.Get("Content-Type"),
+	}
+
+	_, token, _ := splitPath(req.URL.Path)
+	if json.Valid(data) {
+		interaction.Response.Body = json.RawMessage(scrub(string(data), token))
+	} else {
+		interaction.Response.Data = data
+	}
+
+	rec.mu.Lock()
+	rec.cassette.Interactions = append(rec.cassette.Interactions, interaction)
+	rec.mu.Unlock()
+
+	return newResponse(req, interaction.Response, data), nil
+}
+
+// Save writes the recorded interactions to the cassette file. It does nothing
+// when replaying.
+func (rec *Recorder) Save() error {
+	if rec.mode != ModeRecord {
+		return nil
+	}
+
+	rec.mu.Lock()
+	data, err := json.MarshalIndent(rec.cassette, "", "  ")
+	rec.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(rec.path, append(data, '\n'), 0o644)
+}
+
+// Unused returns the recorded interactions that weren't replayed, so tests
+// can check that the bot made all the requests it was expected to.
+func (rec *Recorder) Unused() []Interaction {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	var unused []Interaction
+	for i, used := range rec.used {
+		if !used {
+			unused = append(unused, rec.cassette.Interactions[i])
+		}
+	}
+
+	return unused
+}
+
+// replay returns the response of the first unused interaction matching
+// interaction.
+func (rec *Recorder) replay(req *http.Request, interaction Interaction) (*http.Response, error) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	for i, recorded := range rec.cassette.Interactions {
+		if rec.used[i] || !interaction.matches(recorded) {
+			continue
+		}
+
+		rec.used[i] = true
+
+		body := recorded.Response.Data
+		if recorded.Response.Body != nil {
+			body = recorded.Response.Body
+		}
+
+		return newResponse(req, recorded.Response, body), nil
+	}
+
+	return nil, fmt.Errorf("tgbotapitest: no recorded interaction for %s with params %v", interaction.Method, interaction.Params)
+}
+
+func (i Interaction) matches(other Interaction) bool {
+	return i.Method == other.Method &&
+		len(i.Params) == len(other.Params) && (len(i.Params) == 0 || reflect.DeepEqual(i.Params, other.Params)) &&
+		len(i.Files) == len(other.Files) && (len(i.Files) == 0 || reflect.DeepEqual(i.Files, other.Files))
+}
+
+// newInteraction returns the interaction for req, without its response, and
+// the body of req, which is consumed.
+func newInteraction(req *http.Request) (Interaction, []byte, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return Interaction{}, nil, err
+		}
+	}
+
+	method, token, file := splitPath(req.URL.Path)
+	if file {
+		return Interaction{Method: "file/" + method}, body, nil
+	}
+
+	parsed := req.Clone(req.Context())
+	parsed.Body = io.NopCloser(bytes.NewReader(body))
+
+	call, err := parseCall(parsed, method)
+	if err != nil {
+		return Interaction{}, nil, fmt.Errorf("tgbotapitest: parsing request to %s: %w", method, err)
+	}
+
+	interaction := Interaction{Method: method}
+
+	if len(call.Params) > 0 {
+		interaction.Params = make(map[string]string, len(call.Params))
+		for key, value := range call.Params {
+			interaction.Params[key] = normalize(scrub(value, token))
+		}
+	}
+
+	if len(call.Files) > 0 {
+		interaction.Files = make(map[string]string, len(call.Files))
+		for key, file := range call.Files {
+			sum := sha256.Sum256(file.Data)
+			interaction.Files[key] = file.Name + ":" + hex.EncodeToString(sum[:])
+		}
+	}
+
+	return interaction, body, nil
+}
+
+// normalize re-encodes JSON objects and arrays, so the order of their keys
+// doesn't matter.
+func normalize(value string) string {
+	if !strings.HasPrefix(value, "{") && !strings.HasPrefix(value, "[") {
+		return value
+	}
+
+	var v interface{}
+	if json.Unmarshal([]byte(value), &v) != nil {
+		return value
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return value
+	}
+
+	return string(data)
+}
+
+// splitPath splits the path of a request to the Bot API into the method
+// and the token, or the path of the file and the token for file downloads.
+func splitPath(path string) (method, token string, file bool) {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, "bot") || !strings.Contains(segment, ":") {
+			continue
+		}
+
+		token = strings.TrimPrefix(segment, "bot")
+		if i > 0 && segments[i-1] == "file" {
+			return strings.Join(segments[i+1:], "/"), token, true
+		}
+
+		break
+	}
+
+	return segments[len(segments)-1], token, false
+}
+
+// scrub replaces token in s.
+func scrub(s, token string) string {
+	if token == "" {
+		return s
+	}
+
+	return strings.ReplaceAll(s, token, ScrubbedToken)
+}
+
+// newResponse creates the response to req from a recorded response.
+func newResponse(req *http.Request, recorded RecordedResponse, body []byte) *http.Response {
+	header := make(http.Header)
+	if recorded.ContentType != "" {
+		header.Set("Content-Type", recorded.ContentType)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.Status, http.StatusText(recorded.Status)),
+		StatusCode:    recorded.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
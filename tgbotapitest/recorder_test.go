@@ -0,0 +1,141 @@
+package tgbotapitest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/telemmx/telegram-bot-api/v9"
+)
+
+func TestRecorder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	send := func(bot *tgbotapi.BotAPI) {
+		t.Helper()
+
+		if _, err := bot.Send(tgbotapi.NewMessage(42, "Hello")); err != nil {
+			t.Fatal(err)
+		}
+		msg, err := bot.Send(tgbotapi.NewDocument(42, tgbotapi.FileBytes{Name: "notes.txt", Bytes: []byte("some notes")}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if msg.Document == nil || msg.Document.FileName != "notes.txt" {
+			t.Errorf("unexpected document %+v", msg.Document)
+		}
+	}
+
+	srv := NewServer()
+	rec, err := NewRecorder(path, ModeAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Mode() != ModeRecord {
+		t.Fatalf("expected to record without a cassette, got mode %d", rec.Mode())
+	}
+
+	bot, err := tgbotapi.NewBotAPIWithClient(srv.Token, srv.APIEndpoint(), rec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	send(bot)
+	srv.Close()
+
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), srv.Token) {
+		t.Error("expected the token to be scrubbed from the cassette")
+	}
+
+	rec, err = NewRecorder(path, ModeAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Mode() != ModeReplay {
+		t.Fatalf("expected to replay the cassette, got mode %d", rec.Mode())
+	}
+
+	bot, err = tgbotapi.NewBotAPIWithClient("654321:ABC-DEF1234ghIkl-zyx57W2v1u123ew11", srv.APIEndpoint(), rec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bot.Self.UserName != srv.Self.UserName {
+		t.Errorf("expected the recorded bot user, got %+v", bot.Self)
+	}
+	send(bot)
+
+	if unused := rec.Unused(); len(unused) != 0 {
+		t.Errorf("expected all interactions to be replayed, got %+v", unused)
+	}
+
+	if _, err := bot.Send(tgbotapi.NewMessage(42, "Not recorded")); err == nil || !strings.Contains(err.Error(), "no recorded interaction") {
+		t.Errorf("expected an error for a request that wasn't recorded, got %v", err)
+	}
+}
+
+func TestRecorderReplayWithoutCassette(t *testing.T) {
+	if _, err := NewRecorder(filepath.Join(t.TempDir(), "missing.json"), ModeReplay); err == nil {
+		t.Error("expected an error for a missing cassette")
+	}
+}
+
+func TestRecorderMatchesEncodings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	srv := NewServer()
+	defer srv.Close()
+
+	rec, err := NewRecorder(path, ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bot, err := srv.Bot(tgbotapi.WithHTTPClient(rec), tgbotapi.WithEncoding(tgbotapi.EncodingForm))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := tgbotapi.NewMessage(42, "Hello")
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData("Yes", "yes")),
+	)
+	if _, err := bot.Send(msg); err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	rec, err = NewRecorder(path, ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bot, err = srv.Bot(tgbotapi.WithHTTPClient(rec), tgbotapi.WithEncoding(tgbotapi.EncodingJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bot.Send(msg); err != nil {
+		t.Errorf("expected a JSON request to match a form request, got %v", err)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	a := normalize(`{"b":1,"a":[{"y":2,"x":1}]}`)
+	b := normalize(`{"a": [{"x": 1, "y": 2}], "b": 1}`)
+	if a != b {
+		t.Errorf("expected %s and %s to be equal", a, b)
+	}
+
+	if normalize("{not json") != "{not json" {
+		t.Error("expected invalid JSON to be kept")
+	}
+}
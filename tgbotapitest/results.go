@@ -0,0 +1,497 @@
+package tgbotapitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/telemmx/telegram-bot-api/v9"
+)
+
+// storedFile is a file known to the server.
+type storedFile struct {
+	id   string
+	name string
+	path string
+	data []byte
+	size int
+}
+
+// mediaParams are the params of methods sending a single file, along with the
+// kind of media the file becomes in the message.
+var mediaParams = map[string]string{
+	"sendPhoto":     "photo",
+	"sendAudio":     "audio",
+	"sendDocument":  "document",
+	"sendVideo":     "video",
+	"sendAnimation": "animation",
+	"sendVoice":     "voice",
+	"sendVideoNote": "video_note",
+	"sendSticker":   "sticker",
+}
+
+// messageMethods are the methods sending a message, other than the ones in
+// mediaParams.
+var messageMethods = map[string]bool{
+	"sendMessage":   true,
+	"sendLocation":  true,
+	"sendVenue":     true,
+	"sendContact":   true,
+	"sendPoll":      true,
+	"sendDice":      true,
+	"sendGame":      true,
+	"sendInvoice":   true,
+	"sendChecklist": true,
+}
+
+// result computes the default result of call. It must be called with the
+// lock held.
+func (s *Server) result(call Call) (interface{}, error) {
+	if kind, ok := mediaParams[call.Method]; ok {
+		return s.sendMessage(call, kind)
+	}
+
+	if messageMethods[call.Method] {
+		return s.sendMessage(call, "")
+	}
+
+	switch call.Method {
+	case "getMe":
+		return s.Self, nil
+	case "getChat":
+		return s.chat(call.Params["chat_id"]), nil
+	case "getFile":
+		return s.getFile(call)
+	case "sendMediaGroup":
+		return s.sendMediaGroup(call)
+	case "forwardMessage":
+		return s.forwardMessage(call)
+	case "copyMessage":
+		message, err := s.forwardMessage(call)
+		if err != nil {
+			return nil, err
+		}
+		return tgbotapi.MessageID{MessageID: message.MessageID}, nil
+	case "editMessageText", "editMessageCaption", "editMessageReplyMarkup", "editMessageMedia":
+		return s.editMessage(call)
+	case "deleteMessage":
+		return s.deleteMessage(call)
+	default:
+		return true, nil
+	}
+}
+
+// chat returns the chat identified by a chat_id param, either a numeric ID
+// or the username of a channel.
+func (s *Server) chat(chatID string) *tgbotapi.Chat {
+	if username, ok := strings.CutPrefix(chatID, "@"); ok {
+		h := fnv.New32a()
+		h.Write([]byte(username))
+
+		return &tgbotapi.Chat{
+			ID:       -1000000000000 - int64(h.Sum32()),
+			Type:     "channel",
+			Title:    username,
+			UserName: username,
+		}
+	}
+
+	id, _ := strconv.ParseInt(chatID, 10, 64)
+
+	chat := &tgbotapi.Chat{ID: id}
+	switch {
+	case id > 0:
+		chat.Type = "private"
+		chat.FirstName = "User " + chatID
+	case id < -1000000000000:
+		chat.Type = "supergroup"
+		chat.Title = "Supergroup " + chatID
+	default:
+		chat.Type = "group"
+		chat.Title = "Group " + chatID
+	}
+
+	return chat
+}
+
+// storeMessage gives message an ID and a date if it has none, and stores it.
+// It must be called with the lock held.
+func (s *Server) storeMessage(message *tgbotapi.Message) *tgbotapi.Message {
+	var chatID int64
+	if message.Chat != nil {
+		chatID = message.Chat.ID
+	}
+
+	if message.MessageID == 0 {
+		s.nextMessage[chatID]++
+		message.MessageID = s.nextMessage[chatID]
+	} else if message.MessageID > s.nextMessage[chatID] {
+		s.nextMessage[chatID] = message.MessageID
+	}
+
+	if message.Date == 0 {
+		message.Date = int(time.Now().Unix())
+	}
+
+	if s.messages[chatID] == nil {
+		s.messages[chatID] = make(map[int]*tgbotapi.Message)
+	}
+	s.messages[chatID][message.MessageID] = message
+
+	return message
+}
+
+// newMessage returns a message sent by the bot to the chat of call.
+func (s *Server) newMessage(call Call) *tgbotapi.Message {
+	self := s.Self
+
+	message := &tgbotapi.Message{
+		From:            &self,
+		Chat:            s.chat(call.Params["chat_id"]),
+		MessageThreadID: int(call.Int64("message_thread_id")),
+		Text:            call.Params["text"],
+		Caption:         call.Params["caption"],
+		ReplyMarkup:     inlineKeyboard(call),
+	}
+
+	var reply tgbotapi.ReplyParameters
+	if call.Decode("reply_parameters", &reply) == nil && reply.MessageId != 0 {
+		if replyTo, ok := s.messages[message.Chat.ID][reply.MessageId]; ok {
+			replyToCopy := *replyTo
+			replyToCopy.ReplyToMessage = nil
+			message.ReplyToMessage = &replyToCopy
+		}
+	}
+
+	return message
+}
+
+// sendMessage handles the methods sending a single message, with a file of
+// the given kind if it isn't empty.
+func (s *Server) sendMessage(call Call, kind string) (interface{}, error) {
+	if call.Params["chat_id"] == "" {
+		return nil, BadRequest("chat_id is empty")
+	}
+	if call.Method == "sendMessage" && call.Params["text"] == "" {
+		return nil, BadRequest("message text is empty")
+	}
+
+	message := s.newMessage(call)
+
+	if kind != "" {
+		file, err := s.fileParam(call, kind)
+		if err != nil {
+			return nil, err
+		}
+		setMedia(message, kind, file)
+	}
+
+	switch call.Method {
+	case "sendLocation", "sendVenue":
+		lat, _ := strconv.ParseFloat(call.Params["latitude"], 64)
+		lon, _ := strconv.ParseFloat(call.Params["longitude"], 64)
+		location := tgbotapi.Location{Latitude: lat, Longitude: lon}
+
+		if call.Method == "sendVenue" {
+			message.Venue = &tgbotapi.Venue{
+				Location: location,
+				Title:    call.Params["title"],
+				Address:  call.Params["address"],
+			}
+		}
+		message.Location = &location
+	case "sendContact":
+		message.Contact = &tgbotapi.Contact{
+			PhoneNumber: call.Params["phone_number"],
+			FirstName:   call.Params["first_name"],
+			LastName:    call.Params["last_name"],
+		}
+	case "sendDice":
+		emoji := call.Params["emoji"]
+		if emoji == "" {
+			emoji = "🎲"
+		}
+		message.Dice = &tgbotapi.Dice{Emoji: emoji, Value: 1}
+	case "sendPoll":
+		var options []struct {
+			Text string `json:"text"`
+		}
+		_ = call.Decode("options", &options)
+
+		poll := &tgbotapi.Poll{
+			ID:          strconv.Itoa(len(s.calls)),
+			Question:    call.Params["question"],
+			IsAnonymous: call.Params["is_anonymous"] != "false",
+			Type:        "regular",
+		}
+		if call.Params["type"] != "" {
+			poll.Type = call.Params["type"]
+		}
+		for _, option := range options {
+			poll.Options = append(poll.Options, tgbotapi.PollOption{Text: option.Text})
+		}
+		message.Poll = poll
+	}
+
+	return *s.storeMessage(message), nil
+}
+
+// sendMediaGroup handles sendMediaGroup, returning a message for every item.
+func (s *Server) sendMediaGroup(call Call) (interface{}, error) {
+	var media []struct {
+		Type    string `json:"type"`
+		Media   string `json:"media"`
+		Caption string `json:"caption"`
+	}
+	if err := call.Decode("media", &media); err != nil {
+		return nil, BadRequest("can't parse media JSON object")
+	}
+
+	s.nextGroup++
+	group := strconv.Itoa(s.nextGroup)
+
+	messages := make([]tgbotapi.Message, 0, len(media))
+	for _, item := range media {
+		file, err := s.file(call, item.Media, "")
+		if err != nil {
+			return nil, err
+		}
+
+		message := s.newMessage(call)
+		message.Caption = item.Caption
+		message.MediaGroupID = group
+		setMedia(message, item.Type, file)
+
+		messages = append(messages, *s.storeMessage(message))
+	}
+
+	return messages, nil
+}
+
+// forwardMessage handles forwardMessage and copyMessage, copying a known
+// message to the chat of call.
+func (s *Server) forwardMessage(call Call) (tgbotapi.Message, error) {
+	from := s.chat(call.Params["from_chat_id"])
+
+	original, ok := s.messages[from.ID][int(call.Int64("message_id"))]
+	if !ok {
+		return tgbotapi.Message{}, BadRequest("message to copy not found")
+	}
+
+	message := *original
+	message.MessageID = 0
+	message.Date = 0
+	message.Chat = s.chat(call.Params["chat_id"])
+	message.EditDate = 0
+
+	if call.Method == "copyMessage" {
+		self := s.Self
+		message.From = &self
+		if caption, ok := call.Params["caption"]; ok {
+			message.Caption = caption
+		}
+		message.ReplyMarkup = inlineKeyboard(call)
+	}
+
+	return *s.storeMessage(&message), nil
+}
+
+// editMessage handles the methods editing messages. Edits of inline messages
+// return true, as Telegram does.
+func (s *Server) editMessage(call Call) (interface{}, error) {
+	if call.Params["inline_message_id"] != "" {
+		return true, nil
+	}
+
+	chat := s.chat(call.Params["chat_id"])
+	message, ok := s.messages[chat.ID][int(call.Int64("message_id"))]
+	if !ok {
+		return nil, BadRequest("message to edit not found")
+	}
+
+	edited := *message
+
+	switch call.Method {
+	case "editMessageText":
+		edited.Text = call.Params["text"]
+	case "editMessageCaption":
+		edited.Caption = call.Params["caption"]
+	case "editMessageMedia":
+		var media struct {
+			Type    string `json:"type"`
+			Media   string `json:"media"`
+			Caption string `json:"caption"`
+		}
+		if err := call.Decode("media", &media); err != nil {
+			return nil, BadRequest("can't parse InputMedia JSON object")
+		}
+
+		file, err := s.file(call, media.Media, "")
+		if err != nil {
+			return nil, err
+		}
+
+		edited = tgbotapi.Message{
+			MessageID: message.MessageID,
+			From:      message.From,
+			Date:      message.Date,
+			Chat:      message.Chat,
+			Caption:   media.Caption,
+		}
+		setMedia(&edited, media.Type, file)
+	}
+	edited.ReplyMarkup = inlineKeyboard(call)
+
+	if reflect.DeepEqual(&edited, message) {
+		return nil, BadRequest("message is not modified: specified new message content and reply markup are exactly the same as a current content and reply markup of the message")
+	}
+
+	edited.EditDate = int(time.Now().Unix())
+	*message = edited
+
+	return edited, nil
+}
+
+// deleteMessage handles deleteMessage.
+func (s *Server) deleteMessage(call Call) (interface{}, error) {
+	chat := s.chat(call.Params["chat_id"])
+	messageID := int(call.Int64("message_id"))
+
+	if _, ok := s.messages[chat.ID][messageID]; !ok {
+		return nil, BadRequest("message to delete not found")
+	}
+
+	delete(s.messages[chat.ID], messageID)
+
+	return true, nil
+}
+
+// getFile handles getFile for files known to the server.
+func (s *Server) getFile(call Call) (interface{}, error) {
+	file, ok := s.files[call.Params["file_id"]]
+	if !ok {
+		return nil, BadRequest("wrong file_id or the file is temporarily unavailable")
+	}
+
+	return tgbotapi.File{
+		FileID:       file.id,
+		FileUniqueID: uniqueID(file.id),
+		FileSize:     file.size,
+		FilePath:     file.path,
+	}, nil
+}
+
+// fileParam returns the file sent for param, either uploaded or as a file ID
+// or URL.
+func (s *Server) fileParam(call Call, param string) (*storedFile, error) {
+	if file, ok := call.File(param); ok {
+		return s.addFile(file.Name, file.Data), nil
+	}
+
+	return s.file(call, call.Params[param], param)
+}
+
+// file returns the file referenced by value, which is a file ID, a URL or an
+// attach:// reference to a file uploaded with call.
+func (s *Server) file(call Call, value, param string) (*storedFile, error) {
+	if strings.HasPrefix(value, "attach://") {
+		file, ok := call.File(value)
+		if !ok {
+			return nil, BadRequest(fmt.Sprintf("file %s not found in the request", value))
+		}
+		return s.addFile(file.Name, file.Data), nil
+	}
+
+	if value == "" {
+		return nil, BadRequest(fmt.Sprintf("there is no %s in the request", param))
+	}
+
+	if file, ok := s.files[value]; ok {
+		return file, nil
+	}
+
+	if strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://") {
+		return s.addFile(path.Base(value), nil), nil
+	}
+
+	// Unknown file IDs are accepted, as they may come from fixtures.
+	file := &storedFile{id: value, path: "documents/" + uniqueID(value)}
+	s.files[value] = file
+
+	return file, nil
+}
+
+// addFile stores a new file. It must be called with the lock held.
+func (s *Server) addFile(name string, data []byte) *storedFile {
+	s.nextFile++
+
+	file := &storedFile{
+		id:   fmt.Sprintf("FILE-%d-%s", s.nextFile, uniqueID(name)),
+		name: name,
+		path: fmt.Sprintf("documents/file_%d%s", s.nextFile, path.Ext(name)),
+		data: data,
+		size: len(data),
+	}
+	s.files[file.id] = file
+
+	return file
+}
+
+// uniqueID derives a stable file_unique_id from a file ID.
+func uniqueID(id string) string {
+	h := fnv.New64a()
+	h.Write([]byte(id))
+
+	return strconv.FormatUint(h.Sum64(), 36)
+}
+
+// setMedia sets the media of message for a file of the given kind.
+func setMedia(message *tgbotapi.Message, kind string, file *storedFile) {
+	id, unique := file.id, uniqueID(file.id)
+
+	switch kind {
+	case "photo":
+		message.Photo = []tgbotapi.PhotoSize{
+			{FileID: id + "-s", FileUniqueID: unique + "s", Width: 90, Height: 60, FileSize: file.size / 10},
+			{FileID: id, FileUniqueID: unique, Width: 1280, Height: 853, FileSize: file.size},
+		}
+	case "audio":
+		message.Audio = &tgbotapi.Audio{FileID: id, FileUniqueID: unique, Duration: 1, FileName: file.name, FileSize: file.size}
+	case "document":
+		message.Document = &tgbotapi.Document{FileID: id, FileUniqueID: unique, FileName: file.name, FileSize: file.size}
+	case "video":
+		message.Video = &tgbotapi.Video{FileID: id, FileUniqueID: unique, Width: 1280, Height: 720, Duration: 1, FileName: file.name, FileSize: file.size}
+	case "animation":
+		message.Animation = &tgbotapi.Animation{FileID: id, FileUniqueID: unique, Width: 320, Height: 240, Duration: 1, FileName: file.name, FileSize: file.size}
+		message.Document = &tgbotapi.Document{FileID: id, FileUniqueID: unique, FileName: file.name, FileSize: file.size}
+	case "voice":
+		message.Voice = &tgbotapi.Voice{FileID: id, FileUniqueID: unique, Duration: 1, FileSize: file.size}
+	case "video_note":
+		message.VideoNote = &tgbotapi.VideoNote{FileID: id, FileUniqueID: unique, Length: 240, Duration: 1, FileSize: file.size}
+	case "sticker":
+		message.Sticker = &tgbotapi.Sticker{FileID: id, FileUniqueID: unique, Type: "regular", Width: 512, Height: 512, FileSize: file.size}
+	}
+}
+
+// inlineKeyboard returns the inline keyboard sent with call, which is the
+// only kind of reply markup Telegram includes in messages.
+func inlineKeyboard(call Call) *tgbotapi.InlineKeyboardMarkup {
+	var markup struct {
+		InlineKeyboard json.RawMessage `json:"inline_keyboard"`
+	}
+	if call.Decode("reply_markup", &markup) != nil || markup.InlineKeyboard == nil {
+		return nil
+	}
+
+	var keyboard tgbotapi.InlineKeyboardMarkup
+	if call.Decode("reply_markup", &keyboard) != nil || len(keyboard.InlineKeyboard) == 0 {
+		return nil
+	}
+
+	return &keyboard
+}
@@ -0,0 +1,590 @@
+// Package tgbotapitest provides a fake Telegram Bot API server, so bots can be
+// tested without network access or a real token.
+//
+// The server understands the same requests as Telegram: form encoded, JSON
+// and multipart bodies, including files referenced with attach://. It records
+// every call for assertions, serves scripted updates to getUpdates and returns
+// realistic results, such as a Message with an increasing ID for sendMessage
+// or a File for getFile. Failures such as flood control can be scripted with
+// Fail.
+//
+// Conversation builds on the server to simulate users sending messages and
+// clicking buttons, and Recorder replays responses recorded from Telegram.
+//
+//	srv := tgbotapitest.NewServer()
+//	defer srv.Close()
+//
+//	bot, err := srv.Bot()
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//
+//	bot.Send(tgbotapi.NewMessage(1, "Hello"))
+//
+//	call, _ := srv.LastCall("sendMessage")
+//	if call.Params["text"] != "Hello" {
+//		t.Error("unexpected text")
+//	}
+package tgbotapitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/telemmx/telegram-bot-api/v9"
+)
+
+// Token is the token accepted by servers created with NewServer.
+const Token = "123456:TEST-tgbotapitest-fake-token-0000"
+
+// Server is a fake Telegram Bot API server.
+//
+// It is safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	// Token is the token the server accepts. Requests with another token
+	// fail with 401 Unauthorized.
+	Token string
+	// Self is the bot user returned by getMe and set as the sender of the
+	// messages sent by the bot.
+	Self tgbotapi.User
+
+	mu           sync.Mutex
+	calls        []*Call
+	callsDone    chan struct{}
+	nextCall     int
+	failures     map[string][]Failure
+	handlers     map[string]HandlerFunc
+	batches      [][]tgbotapi.Update
+	nextUpdateID int
+	updatesReady chan struct{}
+	messages     map[int64]map[int]*tgbotapi.Message
+	nextMessage  map[int64]int
+	files        map[string]*storedFile
+	nextFile     int
+	nextGroup    int
+}
+
+// HandlerFunc computes the result of a call. Returning a Failure makes the
+// call fail with its error code and description.
+type HandlerFunc func(call Call) (interface{}, error)
+
+// NewServer starts a fake Telegram Bot API server. It should be closed with
+// Close once done.
+func NewServer() *Server {
+	s := &Server{
+		Token: Token,
+		Self: tgbotapi.User{
+			ID:        123456,
+			IsBot:     true,
+			FirstName: "Test Bot",
+			UserName:  "test_bot",
+		},
+		failures:     make(map[string][]Failure),
+		handlers:     make(map[string]HandlerFunc),
+		nextUpdateID: 1,
+		updatesReady: make(chan struct{}),
+		callsDone:    make(chan struct{}),
+		messages:     make(map[int64]map[int]*tgbotapi.Message),
+		nextMessage:  make(map[int64]int),
+		files:        make(map[string]*storedFile),
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+
+	return s
+}
+
+// APIEndpoint returns the endpoint of the server, for use with
+// tgbotapi.WithAPIEndpoint or BotAPI.SetAPIEndpoint.
+func (s *Server) APIEndpoint() string {
+	return s.URL + "/bot%s/%s"
+}
+
+// FileEndpoint returns the endpoint files are downloaded from, formatted like
+// tgbotapi.FileEndpoint.
+func (s *Server) FileEndpoint() string {
+	return s.URL + "/file/bot%s/%s"
+}
+
+// Bot creates a bot using the server, with opts applied after the options
+// setting the endpoint, HTTP client and Self.
+func (s *Server) Bot(opts ...tgbotapi.Option) (*tgbotapi.BotAPI, error) {
+	opts = append([]tgbotapi.Option{
+		tgbotapi.WithAPIEndpoint(s.APIEndpoint()),
+		tgbotapi.WithHTTPClient(s.Client()),
+		tgbotapi.WithSelf(s.Self),
+	}, opts...)
+
+	return tgbotapi.New(s.Token, opts...)
+}
+
+// Handle sets the handler computing the result of calls to method, replacing
+// the default one.
+func (s *Server) Handle(method string, handler HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handlers[method] = handler
+}
+
+// Fail makes the next calls to method fail, one call for every failure given.
+// Failures for the empty method apply to calls to any method.
+func (s *Server) Fail(method string, failures ...Failure) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures[method] = append(s.failures[method], failures...)
+}
+
+// Calls returns all calls received by the server, in order.
+func (s *Server) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	calls := make([]Call, len(s.calls))
+	for i, call := range s.calls {
+		calls[i] = *call
+	}
+
+	return calls
+}
+
+// CallsTo returns the calls to method received by the server, in order.
+func (s *Server) CallsTo(method string) []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var calls []Call
+	for _, call := range s.calls {
+		if call.Method == method {
+			calls = append(calls, *call)
+		}
+	}
+
+	return calls
+}
+
+// LastCall returns the last call to method received by the server.
+func (s *Server) LastCall(method string) (Call, bool) {
+	calls := s.CallsTo(method)
+	if len(calls) == 0 {
+		return Call{}, false
+	}
+
+	return calls[len(calls)-1], true
+}
+
+// ResetCalls forgets the calls received so far.
+func (s *Server) ResetCalls() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls = nil
+}
+
+// AddUpdates adds a batch of updates, returned together by the next call to
+// getUpdates. Updates without an UpdateID are given one.
+//
+// Like Telegram, the server returns the same updates until they are confirmed
+// by a call to getUpdates with a greater offset, and long polling requests
+// wait for updates to be added.
+func (s *Server) AddUpdates(updates ...tgbotapi.Update) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	batch := make([]tgbotapi.Update, len(updates))
+	for i, update := range updates {
+		if update.UpdateID == 0 {
+			update.UpdateID = s.nextUpdateID
+		}
+		if update.UpdateID >= s.nextUpdateID {
+			s.nextUpdateID = update.UpdateID + 1
+		}
+		batch[i] = update
+	}
+
+	s.batches = append(s.batches, batch)
+
+	close(s.updatesReady)
+	s.updatesReady = make(chan struct{})
+}
+
+// PendingUpdates returns the number of updates that weren't confirmed yet.
+func (s *Server) PendingUpdates() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := 0
+	for _, batch := range s.batches {
+		n += len(batch)
+	}
+
+	return n
+}
+
+// Message returns a message known to the server, sent by the bot or added
+// with StoreMessage, with the edits made to it.
+func (s *Server) Message(chatID int64, messageID int) (tgbotapi.Message, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	message, ok := s.messages[chatID][messageID]
+	if !ok {
+		return tgbotapi.Message{}, false
+	}
+
+	return *message, true
+}
+
+// StoreMessage adds a message to the server, such as one sent by a user, so
+// the bot can reply to it, edit it or delete it. The message is given the next
+// message ID of its chat if it doesn't have one, and a date if it has none.
+// The stored message is returned.
+func (s *Server) StoreMessage(message tgbotapi.Message) tgbotapi.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return *s.storeMessage(&message)
+}
+
+// AddFile adds a file to the server, which can be fetched with getFile and
+// downloaded from FileEndpoint. It returns the ID of the file.
+func (s *Server) AddFile(name string, data []byte) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.addFile(name, data).id
+}
+
+// Call is a request received by the server.
+type Call struct {
+	// Method is the Bot API method called, such as sendMessage.
+	Method string
+	// Params are the params of the call. Params sent as JSON objects or
+	// arrays are kept encoded, and can be decoded with Decode.
+	Params tgbotapi.Params
+	// Files are the files uploaded with the call, by field name.
+	Files map[string]File
+	// Time is when the call was received.
+	Time time.Time
+	// Result is the result returned for the call, or nil if it failed or is
+	// still in progress.
+	Result interface{}
+
+	seq  int
+	done bool
+}
+
+// File is a file uploaded with a call.
+type File struct {
+	// Name is the file name sent by the client.
+	Name string
+	// Data is the content of the file.
+	Data []byte
+}
+
+// Int64 returns a param parsed as an integer, or 0 if it isn't one.
+func (c Call) Int64(key string) int64 {
+	value, _ := strconv.ParseInt(c.Params[key], 10, 64)
+	return value
+}
+
+// Decode decodes a JSON encoded param, such as reply_markup or media, into v.
+func (c Call) Decode(key string, v interface{}) error {
+	value, ok := c.Params[key]
+	if !ok {
+		return fmt.Errorf("param %s is missing", key)
+	}
+
+	return json.Unmarshal([]byte(value), v)
+}
+
+// File returns the file uploaded for a param, either directly as field name or
+// through a reference such as attach://file-0.
+func (c Call) File(param string) (File, bool) {
+	if file, ok := c.Files[param]; ok {
+		return file, true
+	}
+
+	name, ok := strings.CutPrefix(c.Params[param], "attach://")
+	if !ok {
+		name, ok = strings.CutPrefix(param, "attach://")
+	}
+	if !ok {
+		return File{}, false
+	}
+
+	file, ok := c.Files[name]
+	return file, ok
+}
+
+// serveHTTP routes API calls and file downloads.
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if path, ok := strings.CutPrefix(r.URL.Path, "/file/bot"); ok {
+		s.serveFile(w, r, path)
+		return
+	}
+
+	path, ok := strings.CutPrefix(r.URL.Path, "/bot")
+	token, method, found := strings.Cut(path, "/")
+	if !ok || !found || method == "" || strings.Contains(method, "/") {
+		writeFailure(w, Failure{Code: http.StatusNotFound, Description: "Not Found"})
+		return
+	}
+
+	if token != s.Token {
+		writeFailure(w, Failure{Code: http.StatusUnauthorized, Description: "Unauthorized"})
+		return
+	}
+
+	call, err := parseCall(r, method)
+	if err != nil {
+		writeFailure(w, BadRequest(err.Error()))
+		return
+	}
+
+	result, err := s.handle(r, call)
+	if err != nil {
+		var failure Failure
+		if !errors.As(err, &failure) {
+			failure = Failure{Code: http.StatusInternalServerError, Description: "Internal Server Error: " + err.Error()}
+		}
+		writeFailure(w, failure)
+		return
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		writeFailure(w, Failure{Code: http.StatusInternalServerError, Description: "Internal Server Error: " + err.Error()})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(tgbotapi.APIResponse{Ok: true, Result: data})
+}
+
+// handle records call and computes its result.
+func (s *Server) handle(r *http.Request, call Call) (interface{}, error) {
+	s.mu.Lock()
+	s.nextCall++
+	call.seq = s.nextCall
+	recorded := &call
+	s.calls = append(s.calls, recorded)
+	s.mu.Unlock()
+
+	result, err := s.compute(r, call)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err == nil {
+		recorded.Result = result
+	}
+	recorded.done = true
+
+	close(s.callsDone)
+	s.callsDone = make(chan struct{})
+
+	return result, err
+}
+
+// completedCalls returns the calls that completed, in order, and a channel
+// closed when another call completes.
+func (s *Server) completedCalls() ([]Call, <-chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var calls []Call
+	for _, call := range s.calls {
+		if call.done {
+			calls = append(calls, *call)
+		}
+	}
+
+	return calls, s.callsDone
+}
+
+// compute computes the result of call.
+func (s *Server) compute(r *http.Request, call Call) (interface{}, error) {
+	s.mu.Lock()
+
+	for _, method := range []string{call.Method, ""} {
+		if failures := s.failures[method]; len(failures) > 0 {
+			s.failures[method] = failures[1:]
+			s.mu.Unlock()
+			return nil, failures[0]
+		}
+	}
+
+	if handler, ok := s.handlers[call.Method]; ok {
+		s.mu.Unlock()
+		return handler(call)
+	}
+
+	if call.Method == "getUpdates" {
+		s.mu.Unlock()
+		return s.getUpdates(r, call)
+	}
+
+	defer s.mu.Unlock()
+
+	return s.result(call)
+}
+
+// getUpdates returns the first batch of updates that wasn't confirmed,
+// waiting for one to be added for up to the timeout of the call.
+func (s *Server) getUpdates(r *http.Request, call Call) (interface{}, error) {
+	offset := int(call.Int64("offset"))
+	timeout := time.Duration(call.Int64("timeout")) * time.Second
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	for {
+		s.mu.Lock()
+		s.confirmUpdates(offset)
+		if len(s.batches) > 0 {
+			batch := s.batches[0]
+			s.mu.Unlock()
+			return batch, nil
+		}
+		ready := s.updatesReady
+		s.mu.Unlock()
+
+		select {
+		case <-ready:
+		case <-deadline.C:
+			return []tgbotapi.Update{}, nil
+		case <-r.Context().Done():
+			return nil, r.Context().Err()
+		}
+	}
+}
+
+// confirmUpdates forgets updates with an ID lower than offset.
+func (s *Server) confirmUpdates(offset int) {
+	if offset == 0 {
+		return
+	}
+
+	var batches [][]tgbotapi.Update
+	for _, batch := range s.batches {
+		var pending []tgbotapi.Update
+		for _, update := range batch {
+			if update.UpdateID >= offset {
+				pending = append(pending, update)
+			}
+		}
+		if len(pending) > 0 {
+			batches = append(batches, pending)
+		}
+	}
+
+	s.batches = batches
+}
+
+// parseCall reads the params and files of a call from its form, multipart or
+// JSON body.
+func parseCall(r *http.Request, method string) (Call, error) {
+	call := Call{
+		Method: method,
+		Params: make(tgbotapi.Params),
+		Files:  make(map[string]File),
+		Time:   time.Now(),
+	}
+
+	for key, values := range r.URL.Query() {
+		call.Params[key] = values[0]
+	}
+
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	switch contentType {
+	case "multipart/form-data":
+		if err := r.ParseMultipartForm(32 << 20); err != nil {
+			return call, err
+		}
+
+		for key, values := range r.MultipartForm.Value {
+			call.Params[key] = values[0]
+		}
+
+		for key, headers := range r.MultipartForm.File {
+			f, err := headers[0].Open()
+			if err != nil {
+				return call, err
+			}
+
+			data, err := io.ReadAll(f)
+			f.Close()
+			if err != nil {
+				return call, err
+			}
+
+			call.Files[key] = File{Name: headers[0].Filename, Data: data}
+		}
+	case "application/json":
+		var values map[string]json.RawMessage
+		if err := json.NewDecoder(r.Body).Decode(&values); err != nil {
+			return call, err
+		}
+
+		// Strings are unquoted, other values such as null are kept as they
+		// would be form encoded.
+		for key, value := range values {
+			var s string
+			if bytes.HasPrefix(value, []byte(`"`)) && json.Unmarshal(value, &s) == nil {
+				call.Params[key] = s
+			} else {
+				call.Params[key] = string(value)
+			}
+		}
+	default:
+		if err := r.ParseForm(); err != nil {
+			return call, err
+		}
+
+		for key, values := range r.PostForm {
+			call.Params[key] = values[0]
+		}
+	}
+
+	return call, nil
+}
+
+// serveFile serves a file download, whose path is the token and file path.
+func (s *Server) serveFile(w http.ResponseWriter, r *http.Request, path string) {
+	token, filePath, _ := strings.Cut(path, "/")
+	if token != s.Token {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	s.mu.Lock()
+	var file *storedFile
+	for _, f := range s.files {
+		if f.path == filePath && f.data != nil {
+			file = f
+			break
+		}
+	}
+	s.mu.Unlock()
+
+	if file == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	http.ServeContent(w, r, file.name, time.Time{}, strings.NewReader(string(file.data)))
+}
@@ -0,0 +1,272 @@
+package tgbotapitest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/telemmx/telegram-bot-api/v9"
+)
+
+func newBot(t *testing.T, opts ...tgbotapi.Option) (*Server, *tgbotapi.BotAPI) {
+	t.Helper()
+
+	srv := NewServer()
+	t.Cleanup(srv.Close)
+
+	bot, err := srv.Bot(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return srv, bot
+}
+
+func TestSendMessage(t *testing.T) {
+	for _, encoding := range []tgbotapi.Encoding{tgbotapi.EncodingForm, tgbotapi.EncodingJSON} {
+		srv, bot := newBot(t, tgbotapi.WithEncoding(encoding))
+
+		msg := tgbotapi.NewMessage(42, "Hello")
+		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData("Yes", "yes")),
+		)
+
+		first, err := bot.Send(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		second, err := bot.Send(tgbotapi.NewMessage(42, "World"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if first.MessageID != 1 || second.MessageID != 2 || first.Chat.ID != 42 || first.Chat.Type != "private" || first.From.ID != srv.Self.ID {
+			t.Errorf("unexpected messages %+v and %+v", first, second)
+		}
+		if first.ReplyMarkup == nil || first.ReplyMarkup.InlineKeyboard[0][0].Text != "Yes" {
+			t.Errorf("expected the inline keyboard in the message, got %+v", first.ReplyMarkup)
+		}
+
+		call, ok := srv.LastCall("sendMessage")
+		if !ok || call.Params["text"] != "World" || call.Int64("chat_id") != 42 {
+			t.Errorf("unexpected last call %+v", call)
+		}
+		if len(srv.CallsTo("sendMessage")) != 2 {
+			t.Errorf("expected 2 calls, got %d", len(srv.CallsTo("sendMessage")))
+		}
+	}
+}
+
+func TestUploadAndDownload(t *testing.T) {
+	srv, bot := newBot(t)
+
+	msg, err := bot.Send(tgbotapi.NewDocument(1, tgbotapi.FileBytes{Name: "notes.txt", Bytes: []byte("some notes")}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Document == nil || msg.Document.FileName != "notes.txt" || msg.Document.FileSize != 10 {
+		t.Fatalf("unexpected document %+v", msg.Document)
+	}
+
+	call, _ := srv.LastCall("sendDocument")
+	if file, ok := call.File("document"); !ok || string(file.Data) != "some notes" {
+		t.Errorf("expected the uploaded file to be recorded, got %+v", call.Files)
+	}
+
+	file, err := bot.GetFile(tgbotapi.FileConfig{FileID: msg.Document.FileID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := http.Get(srv.URL + "/file/bot" + srv.Token + "/" + file.FilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	data, _ := io.ReadAll(resp.Body)
+	if string(data) != "some notes" {
+		t.Errorf("unexpected file content %q", data)
+	}
+
+	var buf bytes.Buffer
+	if _, err := bot.DownloadFile(context.Background(), msg.Document.FileID, &buf); err != nil || buf.String() != "some notes" {
+		t.Errorf("unexpected download %q, %v", buf.String(), err)
+	}
+
+	if _, err := bot.GetFile(tgbotapi.FileConfig{FileID: "unknown"}); !errors.Is(err, tgbotapi.ErrBadRequest) {
+		t.Errorf("expected a bad request for an unknown file, got %v", err)
+	}
+}
+
+func TestMediaGroup(t *testing.T) {
+	srv, bot := newBot(t)
+
+	photo := tgbotapi.NewInputMediaPhoto(tgbotapi.FileBytes{Name: "a.jpg", Bytes: []byte("a")})
+	photo.Caption = "first"
+	document := tgbotapi.NewInputMediaDocument(tgbotapi.FileID("existing"))
+
+	messages, err := bot.SendMediaGroup(tgbotapi.NewMediaGroup(1, []interface{}{photo, document}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(messages) != 2 || messages[0].MediaGroupID == "" || messages[0].MediaGroupID != messages[1].MediaGroupID {
+		t.Fatalf("unexpected messages %+v", messages)
+	}
+	if len(messages[0].Photo) == 0 || messages[0].Caption != "first" || messages[1].Document.FileID != "existing" {
+		t.Errorf("unexpected media %+v, %+v", messages[0], messages[1])
+	}
+
+	call, _ := srv.LastCall("sendMediaGroup")
+	var media []struct {
+		Media string `json:"media"`
+	}
+	if err := call.Decode("media", &media); err != nil {
+		t.Fatal(err)
+	}
+	if file, ok := call.File(media[0].Media); !ok || string(file.Data) != "a" {
+		t.Errorf("expected %s to be uploaded, got %+v", media[0].Media, call.Files)
+	}
+}
+
+func TestEditAndDelete(t *testing.T) {
+	srv, bot := newBot(t)
+
+	msg, err := bot.Send(tgbotapi.NewMessage(1, "before"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	edited, err := bot.Send(tgbotapi.NewEditMessageText(1, msg.MessageID, "after"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if edited.Text != "after" || edited.EditDate == 0 {
+		t.Errorf("unexpected edited message %+v", edited)
+	}
+
+	if stored, _ := srv.Message(1, msg.MessageID); stored.Text != "after" {
+		t.Errorf("expected the stored message to be edited, got %+v", stored)
+	}
+
+	_, err = bot.Send(tgbotapi.NewEditMessageText(1, msg.MessageID, "after"))
+	if !errors.Is(err, tgbotapi.ErrMessageNotModified) {
+		t.Errorf("expected ErrMessageNotModified, got %v", err)
+	}
+
+	if _, err := bot.Request(tgbotapi.NewDeleteMessage(1, msg.MessageID)); err != nil {
+		t.Fatal(err)
+	}
+	_, err = bot.Request(tgbotapi.NewDeleteMessage(1, msg.MessageID))
+	if !errors.Is(err, tgbotapi.ErrMessageToDeleteNotFound) {
+		t.Errorf("expected ErrMessageToDeleteNotFound, got %v", err)
+	}
+}
+
+func TestGetUpdates(t *testing.T) {
+	srv, bot := newBot(t)
+
+	srv.AddUpdates(
+		tgbotapi.Update{Message: &tgbotapi.Message{Text: "one"}},
+		tgbotapi.Update{Message: &tgbotapi.Message{Text: "two"}},
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	config := tgbotapi.NewUpdate(0)
+	config.Timeout = 60
+	updates := bot.GetUpdatesChanWithContext(ctx, config)
+
+	for _, text := range []string{"one", "two"} {
+		update := <-updates
+		if update.Message.Text != text {
+			t.Errorf("expected %q, got %q", text, update.Message.Text)
+		}
+	}
+
+	srv.AddUpdates(tgbotapi.Update{Message: &tgbotapi.Message{Text: "three"}})
+
+	select {
+	case update := <-updates:
+		if update.UpdateID != 3 || update.Message.Text != "three" {
+			t.Errorf("unexpected update %+v", update)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the long polling request to return the new update")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for srv.PendingUpdates() != 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if srv.PendingUpdates() != 0 {
+		t.Errorf("expected all updates to be confirmed, %d pending", srv.PendingUpdates())
+	}
+}
+
+func TestFailures(t *testing.T) {
+	retry := tgbotapi.NewRetryPolicy()
+	retry.MaxDelay = 10 * time.Millisecond
+
+	srv, bot := newBot(t, tgbotapi.WithRetryPolicy(retry))
+
+	srv.Fail("sendMessage", TooManyRequests(1))
+	var apiErr *tgbotapi.Error
+	if _, err := bot.Send(tgbotapi.NewMessage(1, "text")); !errors.As(err, &apiErr) || apiErr.RetryAfter != 1 {
+		t.Fatalf("expected a flood wait longer than MaxDelay to fail, got %v", err)
+	}
+	if calls := len(srv.CallsTo("sendMessage")); calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+
+	retry.MaxDelay = 2 * time.Second
+	srv.Fail("sendMessage", TooManyRequests(1))
+	if _, err := bot.Send(tgbotapi.NewMessage(1, "text")); err != nil {
+		t.Fatalf("expected the flood wait to be retried, got %v", err)
+	}
+	if calls := len(srv.CallsTo("sendMessage")); calls != 3 {
+		t.Errorf("expected 3 calls, got %d", calls)
+	}
+
+	srv.Fail("", BotBlocked())
+	if _, err := bot.Send(tgbotapi.NewMessage(1, "text")); !errors.Is(err, tgbotapi.ErrBotBlocked) {
+		t.Errorf("expected ErrBotBlocked, got %v", err)
+	}
+
+	bot.EnableChatMigration(nil)
+	srv.Fail("sendMessage", ChatMigrated(-1001234567890))
+	msg, err := bot.Send(tgbotapi.NewMessage(-1234, "text"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Chat.ID != -1001234567890 || msg.Chat.Type != "supergroup" {
+		t.Errorf("expected the message to be sent to the supergroup, got %+v", msg.Chat)
+	}
+
+	srv.Handle("getChat", func(call Call) (interface{}, error) {
+		return nil, Forbidden("bot was kicked from the group chat")
+	})
+	if _, err := bot.GetChat(tgbotapi.ChatInfoConfig{ChatConfig: tgbotapi.ChatConfig{ChatID: -1}}); !errors.Is(err, tgbotapi.ErrBotKicked) {
+		t.Errorf("expected ErrBotKicked, got %v", err)
+	}
+}
+
+func TestWrongToken(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	bot, err := tgbotapi.New("654321:ABC-DEF1234ghIkl-zyx57W2v1u123ew11", tgbotapi.WithAPIEndpoint(srv.APIEndpoint()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bot.Init(context.Background()); !errors.Is(err, tgbotapi.ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+}
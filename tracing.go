@@ -0,0 +1,111 @@
+package tgbotapi
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Tracer starts spans for the work done by a bot, so it can be correlated
+// across updates and the requests they trigger. It is meant to be implemented
+// by adapters for tracing libraries such as OpenTelemetry, mapping attributes
+// to the library's own types.
+//
+// The bot creates the following spans:
+//
+//   - telegram.update, for every update handled by a Dispatcher or with
+//     TraceUpdate, covering its handling, with the telegram.update_id and
+//     telegram.chat_id attributes
+//   - telegram.request, for every request made, including its middleware and
+//     retries, with the telegram.method and telegram.chat_id attributes
+//   - telegram.attempt, for every HTTP request made to Telegram, as a child
+//     of telegram.request
+//
+// Requests made with the context passed to the handler of an update are
+// children of the update's span.
+type Tracer interface {
+	// Start starts a span named name as a child of the span in ctx, if any.
+	// It returns a context carrying the new span.
+	Start(ctx context.Context, name string, attrs ...slog.Attr) (context.Context, Span)
+}
+
+// Span is a unit of work started by a Tracer.
+type Span interface {
+	// SetAttributes adds attributes to the span.
+	SetAttributes(attrs ...slog.Attr)
+	// RecordError records that the work failed with err.
+	RecordError(err error)
+	// End ends the span.
+	End()
+}
+
+// noopTracer is the Tracer used by bots without one.
+type noopTracer struct{}
+
+func (noopTracer) Start(ctx context.Context, _ string, _ ...slog.Attr) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...slog.Attr) {}
+func (noopSpan) RecordError(error)          {}
+func (noopSpan) End()                       {}
+
+// startSpan starts a span with the bot's Tracer.
+func (bot *BotAPI) startSpan(ctx context.Context, name string, attrs ...slog.Attr) (context.Context, Span) {
+	if bot.Tracer == nil {
+		return noopTracer{}.Start(ctx, name, attrs...)
+	}
+
+	return bot.Tracer.Start(ctx, name, attrs...)
+}
+
+// endSpan records the outcome of the work traced by span and ends it.
+func endSpan(span Span, err error) {
+	span.SetAttributes(slog.String("telegram.outcome", requestOutcome(err)))
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+}
+
+// TraceUpdate handles update with handle within a telegram.update span,
+// started as a child of the span in ctx. Requests made with the context
+// passed to handle are children of the update's span, which ends once handle
+// returns, recording its error.
+//
+// source is where the update comes from, UpdateSourcePolling or
+// UpdateSourceWebhook, or empty if it isn't known. Updates dispatched by a
+// Dispatcher are traced already.
+func (bot *BotAPI) TraceUpdate(ctx context.Context, source string, update Update, handle func(ctx context.Context) error) error {
+	attrs := []slog.Attr{slog.Int("telegram.update_id", update.UpdateID)}
+
+	if source != "" {
+		attrs = append(attrs, slog.String("telegram.source", source))
+	}
+
+	if chat := update.FromChat(); chat != nil {
+		attrs = append(attrs, slog.Int64("telegram.chat_id", chat.ID))
+	}
+
+	ctx, span := bot.startSpan(ctx, "telegram.update", attrs...)
+	err := handle(ctx)
+	endSpan(span, err)
+
+	return err
+}
+
+// requestAttrs returns the span attributes of a request.
+func requestAttrs(req *APIRequest) []slog.Attr {
+	attrs := []slog.Attr{slog.String("telegram.method", req.Endpoint)}
+
+	if chatID, ok := req.Params["chat_id"]; ok {
+		attrs = append(attrs, slog.String("telegram.chat_id", chatID))
+	}
+
+	if len(req.Files) > 0 {
+		attrs = append(attrs, slog.Int("telegram.files", len(req.Files)))
+	}
+
+	return attrs
+}
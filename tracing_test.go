@@ -0,0 +1,132 @@
+package tgbotapi
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type recordedSpan struct {
+	name   string
+	parent *recordedSpan
+	attrs  map[string]string
+	err    error
+	ended  bool
+}
+
+func (s *recordedSpan) SetAttributes(attrs ...slog.Attr) {
+	for _, attr := range attrs {
+		s.attrs[attr.Key] = attr.Value.String()
+	}
+}
+
+func (s *recordedSpan) RecordError(err error) {
+	s.err = err
+}
+
+func (s *recordedSpan) End() {
+	s.ended = true
+}
+
+type spanKey struct{}
+
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+func (tr *recordingTracer) Start(ctx context.Context, name string, attrs ...slog.Attr) (context.Context, Span) {
+	parent, _ := ctx.Value(spanKey{}).(*recordedSpan)
+	span := &recordedSpan{name: name, parent: parent, attrs: make(map[string]string)}
+	span.SetAttributes(attrs...)
+
+	tr.mu.Lock()
+	tr.spans = append(tr.spans, span)
+	tr.mu.Unlock()
+
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func TestTracingFromUpdateToRequest(t *testing.T) {
+	bot := newLocalBot(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/getUpdates"):
+			if r.FormValue("offset") == "" {
+				writeResult(w, `[{"update_id":10,"message":{"message_id":1,"chat":{"id":5},"text":"hi"}}]`)
+				return
+			}
+			<-r.Context().Done()
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"ok":false,"error_code":403,"description":"Forbidden: bot was blocked by the user"}`))
+		}
+	})
+
+	tracer := &recordingTracer{}
+	bot.Tracer = tracer
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d := NewDispatcher(bot)
+	d.ErrorHandler = func(ctx context.Context, update Update, err error) {}
+	d.Fallback(func(ctx context.Context, update Update) error {
+		defer cancel()
+
+		if span, _ := ctx.Value(spanKey{}).(*recordedSpan); span == nil || span.ended {
+			t.Error("expected the update span to last while handling the update")
+		}
+
+		_, err := bot.SendWithContext(ctx, NewMessage(5, "hello"))
+		return err
+	})
+	updates := bot.GetUpdatesChanWithContext(ctx, NewUpdate(0))
+	d.Run(ctx, updates)
+	for range updates {
+	}
+
+	tracer.mu.Lock()
+	defer tracer.mu.Unlock()
+
+	var send *recordedSpan
+	for _, span := range tracer.spans {
+		if span.name == "telegram.request" && span.attrs["telegram.method"] == "sendMessage" {
+			send = span
+		}
+	}
+	if send == nil {
+		t.Fatal("expected a span for sendMessage")
+	}
+
+	update10 := send.parent
+	if update10 == nil || update10.name != "telegram.update" || update10.attrs["telegram.update_id"] != "10" || update10.attrs["telegram.chat_id"] != "5" {
+		t.Fatalf("expected sendMessage to be a child of the update span, got %+v", update10)
+	}
+	if !update10.ended || update10.err == nil || update10.attrs["telegram.outcome"] != "403" {
+		t.Errorf("expected the update span to be ended with the handler's error, got %+v", update10)
+	}
+
+	if send.attrs["telegram.chat_id"] != "5" || send.attrs["telegram.outcome"] != "403" || send.err == nil || !send.ended {
+		t.Errorf("unexpected request span %+v", send)
+	}
+
+	var attempts int
+	for _, span := range tracer.spans {
+		if span.name == "telegram.attempt" && span.parent == send {
+			attempts++
+		}
+	}
+	if attempts != 1 {
+		t.Errorf("expected 1 attempt span for sendMessage, got %d", attempts)
+	}
+}
+
+func TestFromChatInlineCallback(t *testing.T) {
+	update := Update{CallbackQuery: &CallbackQuery{InlineMessageID: "inline"}}
+	if chat := update.FromChat(); chat != nil {
+		t.Errorf("expected no chat for inline callback queries, got %+v", chat)
+	}
+}
@@ -232,6 +232,9 @@ func (u *Update) FromChat() *Chat {
 	case u.MessageReactionCount != nil:
 		return u.MessageReactionCount.Chat
 	case u.CallbackQuery != nil:
+		if u.CallbackQuery.Message == nil {
+			return nil
+		}
 		return u.CallbackQuery.Message.Chat
 	case u.MyChatMember != nil:
 		return u.MyChatMember.Chat
@@ -248,6 +251,61 @@ func (u *Update) FromChat() *Chat {
 	}
 }
 
+// Type returns the type of the update, one of the UpdateType constants, or an
+// empty string if the update has no known content.
+func (u *Update) Type() string {
+	switch {
+	case u.Message != nil:
+		return UpdateTypeMessage
+	case u.EditedMessage != nil:
+		return UpdateTypeEditedMessage
+	case u.ChannelPost != nil:
+		return UpdateTypeChannelPost
+	case u.EditedChannelPost != nil:
+		return UpdateTypeEditedChannelPost
+	case u.BusinessConnection != nil:
+		return UpdateTypeBusinessConnection
+	case u.BusinessMessage != nil:
+		return UpdateTypeBusinessMessage
+	case u.EditedBusinessMessage != nil:
+		return UpdateTypeEditedBusinessMessage
+	case u.DeletedBusinessMessages != nil:
+		return UpdateTypeDeletedBusinessMessages
+	case u.MessageReaction != nil:
+		return UpdateTypeMessageReaction
+	case u.MessageReactionCount != nil:
+		return UpdateTypeMessageReactionCount
+	case u.InlineQuery != nil:
+		return UpdateTypeInlineQuery
+	case u.ChosenInlineResult != nil:
+		return UpdateTypeChosenInlineResult
+	case u.CallbackQuery != nil:
+		return UpdateTypeCallbackQuery
+	case u.ShippingQuery != nil:
+		return UpdateTypeShippingQuery
+	case u.PreCheckoutQuery != nil:
+		return UpdateTypePreCheckoutQuery
+	case u.PurchasedPaidMedia != nil, u.PaidMediaPurchased != nil:
+		return UpdateTypePurchasedPaidMedia
+	case u.Poll != nil:
+		return UpdateTypePoll
+	case u.PollAnswer != nil:
+		return UpdateTypePollAnswer
+	case u.MyChatMember != nil:
+		return UpdateTypeMyChatMember
+	case u.ChatMember != nil:
+		return UpdateTypeChatMember
+	case u.ChatJoinRequest != nil:
+		return UpdateTypeChatJoinRequest
+	case u.ChatBoost != nil:
+		return UpdateTypeChatBoost
+	case u.RemovedChatBoost != nil:
+		return UpdateTypeRemovedChatBoost
+	default:
+		return ""
+	}
+}
+
 // UpdatesChannel is the channel for getting updates.
 type UpdatesChannel <-chan Update
 
@@ -869,6 +927,65 @@ func (m *Message) Time() time.Time {
 	return time.Unix(int64(m.Date), 0)
 }
 
+// ContentType returns the content of the message, one of the ContentType
+// constants, or an empty string for other service messages.
+func (m *Message) ContentType() string {
+	switch {
+	case m.Text != "":
+		return ContentTypeText
+	case m.Animation != nil:
+		// Animations are also sent as a document.
+		return ContentTypeAnimation
+	case m.Audio != nil:
+		return ContentTypeAudio
+	case m.Document != nil:
+		return ContentTypeDocument
+	case m.PaidMedia != nil:
+		return ContentTypePaidMedia
+	case len(m.Photo) > 0:
+		return ContentTypePhoto
+	case m.Sticker != nil:
+		return ContentTypeSticker
+	case m.Story != nil:
+		return ContentTypeStory
+	case m.Video != nil:
+		return ContentTypeVideo
+	case m.VideoNote != nil:
+		return ContentTypeVideoNote
+	case m.Voice != nil:
+		return ContentTypeVoice
+	case m.Checklist != nil:
+		return ContentTypeChecklist
+	case m.Contact != nil:
+		return ContentTypeContact
+	case m.Dice != nil:
+		return ContentTypeDice
+	case m.Game != nil:
+		return ContentTypeGame
+	case m.Poll != nil:
+		return ContentTypePoll
+	case m.Venue != nil:
+		// Venues also have a location.
+		return ContentTypeVenue
+	case m.Location != nil:
+		return ContentTypeLocation
+	case m.Invoice != nil:
+		return ContentTypeInvoice
+	case m.SuccessfulPayment != nil:
+		return ContentTypeSuccessfulPayment
+	case len(m.NewChatMembers) > 0:
+		return ContentTypeNewChatMembers
+	case m.LeftChatMember != nil:
+		return ContentTypeLeftChatMember
+	case m.PinnedMessage != nil:
+		return ContentTypePinnedMessage
+	case m.WebAppData != nil:
+		return ContentTypeWebAppData
+	default:
+		return ""
+	}
+}
+
 // IsCommand returns true if message starts with a "bot_command" entity.
 func (m *Message) IsCommand() bool {
 	if m.Entities == nil || len(m.Entities) == 0 {
@@ -2112,6 +2229,24 @@ type ChatLocation struct {
 	Address string `json:"address"`
 }
 
+// BotName represents the bot's name.
+type BotName struct {
+	// The bot's name
+	Name string `json:"name"`
+}
+
+// BotDescription represents the bot's description.
+type BotDescription struct {
+	// The bot's description
+	Description string `json:"description"`
+}
+
+// BotShortDescription represents the bot's short description.
+type BotShortDescription struct {
+	// The bot's short description
+	ShortDescription string `json:"short_description"`
+}
+
 // BotCommand represents a bot command.
 type BotCommand struct {
 	// Command text of the command, 1-32 characters.
@@ -2135,6 +2270,57 @@ type StarAmount struct {
 	NanostarAmount int `json:"nanostar_amount,omitempty"`
 }
 
+// StarTransactions contains a list of Telegram Star transactions.
+type StarTransactions struct {
+	// The list of transactions
+	Transactions []StarTransaction `json:"transactions"`
+}
+
+// StarTransaction describes a Telegram Star transaction.
+type StarTransaction struct {
+	// Unique identifier of the transaction
+	ID string `json:"id"`
+	// Integer amount of Telegram Stars transferred by the transaction
+	Amount int `json:"amount"`
+	// Optional. The number of 1/1000000000 shares of Telegram Stars transferred by the transaction
+	NanostarAmount int `json:"nanostar_amount,omitempty"`
+	// Date the transaction was created in Unix time
+	Date int `json:"date"`
+	// Optional. Source of an incoming transaction
+	Source *TransactionPartner `json:"source,omitempty"`
+	// Optional. Receiver of an outgoing transaction
+	Receiver *TransactionPartner `json:"receiver,omitempty"`
+}
+
+// TransactionPartner describes the source or the receiver of a Telegram Star
+// transaction.
+//
+// It contains the fields for all types of partners, different types only
+// support specific (or no) fields.
+type TransactionPartner struct {
+	// Type of the transaction partner, such as "user", "chat",
+	// "affiliate_program", "fragment", "telegram_ads", "telegram_api" or "other"
+	Type string `json:"type"`
+	// Optional. Type of the transaction with a user
+	TransactionType string `json:"transaction_type,omitempty"`
+	// Optional. Information about the user
+	User *User `json:"user,omitempty"`
+	// Optional. Information about the chat
+	Chat *Chat `json:"chat,omitempty"`
+	// Optional. Bot-specified invoice payload
+	InvoicePayload string `json:"invoice_payload,omitempty"`
+	// Optional. The duration of the paid subscription
+	SubscriptionPeriod int `json:"subscription_period,omitempty"`
+	// Optional. The gift sent by the bot
+	Gift *Gift `json:"gift,omitempty"`
+	// Optional. Number of months the gifted Telegram Premium subscription will be active for
+	PremiumSubscriptionDuration int `json:"premium_subscription_duration,omitempty"`
+	// Optional. The number of successful requests that exceeded regular limits and were therefore billed
+	RequestCount int `json:"request_count,omitempty"`
+	// Optional. The number of Telegram Stars received by the bot for each 1000 Telegram Stars received by the affiliate program sponsor
+	CommissionPerMille int `json:"commission_per_mille,omitempty"`
+}
+
 // BotCommandScope represents the scope to which bot commands are applied.
 //
 // It contains the fields for all types of scopes, different types only support
@@ -2793,6 +2979,12 @@ type ChatBoost struct {
 	Source *ChatBoostSource `json:"source,omitempty"`
 }
 
+// UserChatBoosts represents a list of boosts added to a chat by a user.
+type UserChatBoosts struct {
+	// The list of boosts added to the chat by the user
+	Boosts []ChatBoost `json:"boosts"`
+}
+
 type ChatBoostUpdated struct {
 	// Chat which was boosted
 	Chat *Chat `json:"chat,omitempty"`
@@ -3148,6 +3340,22 @@ type ChatBackground struct {
 	Type *BackgroundType `json:"type,omitempty"`
 }
 
+// ForumTopicInfo represents a forum topic, as returned by createForumTopic.
+// It is the ForumTopic type of the Bot API, whose name is taken by a config
+// in this package.
+type ForumTopicInfo struct {
+	// Unique identifier of the forum topic
+	MessageThreadID int `json:"message_thread_id"`
+	// Name of the topic
+	Name string `json:"name"`
+	// Color of the topic icon in RGB format
+	IconColor int `json:"icon_color"`
+	// Unique identifier of the custom emoji shown as the topic icon
+	//
+	// optional
+	IconCustomEmojiID string `json:"icon_custom_emoji_id,omitempty"`
+}
+
 type ForumTopicCreated struct {
 	// Name of the topic
 	Name string `json:"name"`
@@ -347,6 +347,7 @@ var (
 	_ Chattable = VideoNoteConfig{}
 	_ Chattable = VoiceConfig{}
 	_ Chattable = WebhookConfig{}
+	_ Chattable = WebhookInfo{}
 )
 
 // Ensure all Fileable types are correct.
@@ -363,11 +364,13 @@ var (
 	_ Fileable = (*EditMessageMediaConfig)(nil)
 	_ Fileable = (*SetChatPhotoConfig)(nil)
 	_ Fileable = (*UploadStickerFileConfig)(nil)
-	// _ Fileable = (*CreateNewStickerSetConfig)(nil)
-	// _ Fileable = (*AddStickerConfig)(nil)
+	_ Fileable = (*CreateNewStickerSetConfig)(nil)
+	_ Fileable = (*AddStickerToSetConfig)(nil)
+	_ Fileable = (*ReplaceStickerInSetConfig)(nil)
+	_ Fileable = (*SendStickerConfig)(nil)
 	_ Fileable = (*MediaGroupConfig)(nil)
 	_ Fileable = (*WebhookConfig)(nil)
-	// _ Fileable = (*SetStickerSetThumbConfig)(nil)
+	_ Fileable = (*SetStickerSetThumbnailConfig)(nil)
 )
 
 // Ensure all RequestFileData types are correct.
@@ -375,6 +378,8 @@ var (
 	_ RequestFileData = (*FilePath)(nil)
 	_ RequestFileData = (*FileBytes)(nil)
 	_ RequestFileData = (*FileReader)(nil)
+	_ RequestFileData = (*FileReaderAt)(nil)
+	_ RequestFileData = (*FileFS)(nil)
 	_ RequestFileData = (*FileURL)(nil)
 	_ RequestFileData = (*FileID)(nil)
 	_ RequestFileData = (*fileAttach)(nil)
@@ -0,0 +1,272 @@
+package tgbotapi
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// uploadServer records the documents uploaded with sendDocument and the
+// content length of the requests, failing the first failures requests.
+type uploadServer struct {
+	mu        sync.Mutex
+	failures  int
+	documents []string
+	lengths   []int64
+	names     []string
+}
+
+func (s *uploadServer) handle(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.ContentLength >= 0 && r.ContentLength != int64(len(body)) {
+		http.Error(w, "wrong content length", http.StatusBadRequest)
+		return
+	}
+	s.lengths = append(s.lengths, r.ContentLength)
+
+	if err := r.ParseMultipartForm(1 << 20); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	file, header, err := r.FormFile("document")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	data, _ := io.ReadAll(file)
+	s.documents = append(s.documents, string(data))
+	s.names = append(s.names, header.Filename)
+
+	if s.failures > 0 {
+		s.failures--
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = io.WriteString(w, `{"ok":false,"error_code":502,"description":"Bad Gateway"}`)
+		return
+	}
+
+	writeResult(w, `{"message_id":1}`)
+}
+
+func TestUploadContentLength(t *testing.T) {
+	server := &uploadServer{}
+	bot := newLocalBot(t, server.handle)
+
+	path := filepath.Join(t.TempDir(), "path.txt")
+	if err := os.WriteFile(path, []byte("from path"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	fsys := fstest.MapFS{"assets/fs.txt": {Data: []byte("from fs")}}
+
+	files := []RequestFileData{
+		FileBytes{Name: "bytes.txt", Bytes: []byte("from bytes")},
+		FilePath(path),
+		FileReaderAt{Name: "reader_at.txt", Reader: strings.NewReader("from reader at"), Size: 14},
+		FileFS{FS: fsys, Path: "assets/fs.txt"},
+		FileReader{Name: "reader.txt", Reader: strings.NewReader("from reader")},
+	}
+
+	for _, file := range files {
+		if _, err := bot.Send(NewDocument(1, file)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := "from bytes,from path,from reader at,from fs,from reader"
+	if got := strings.Join(server.documents, ","); got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+	if server.names[3] != "fs.txt" {
+		t.Errorf("expected the base name of the fs.FS file, got %s", server.names[3])
+	}
+	for i, length := range server.lengths {
+		if known := i < 4; known != (length > 0) {
+			t.Errorf("unexpected content length %d for %T", length, files[i])
+		}
+	}
+}
+
+func TestUploadRetry(t *testing.T) {
+	retry := NewRetryPolicy()
+	retry.BaseDelay = time.Millisecond
+	retry.Methods = map[string]bool{"sendDocument": true}
+
+	for _, file := range []RequestFileData{
+		FileBytes{Name: "bytes.txt", Bytes: []byte("content")},
+		FileReaderAt{Name: "reader_at.txt", Reader: strings.NewReader("content"), Size: 7},
+	} {
+		server := &uploadServer{failures: 2}
+		bot := newLocalBot(t, server.handle)
+		bot.Retry = retry
+
+		if _, err := bot.Send(NewDocument(1, file)); err != nil {
+			t.Fatalf("expected the upload of %T to be retried, got %v", file, err)
+		}
+		if got := strings.Join(server.documents, ","); got != "content,content,content" {
+			t.Errorf("expected the whole file on every attempt, got %s", got)
+		}
+	}
+
+	server := &uploadServer{failures: 1}
+	bot := newLocalBot(t, server.handle)
+	bot.Retry = retry
+
+	if _, err := bot.Send(NewDocument(1, FileReader{Name: "reader.txt", Reader: strings.NewReader("content")})); err == nil {
+		t.Error("expected the upload of a FileReader to fail")
+	}
+	if len(server.documents) != 1 {
+		t.Errorf("expected a FileReader not to be retried, got %d attempts", len(server.documents))
+	}
+}
+
+// multipartServer records the params and files of the last multipart request,
+// with the files keyed by field as "filename:content".
+type multipartServer struct {
+	mu     sync.Mutex
+	method string
+	params map[string]string
+	files  map[string]string
+}
+
+func (s *multipartServer) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := r.ParseMultipartForm(1 << 20); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.method = r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+	s.params = make(map[string]string)
+	s.files = make(map[string]string)
+	for field, values := range r.MultipartForm.Value {
+		s.params[field] = values[0]
+	}
+	for field, headers := range r.MultipartForm.File {
+		file, _ := headers[0].Open()
+		data, _ := io.ReadAll(file)
+		file.Close()
+		s.files[field] = headers[0].Filename + ":" + string(data)
+	}
+
+	if s.method == "sendMediaGroup" {
+		writeResult(w, `[{"message_id":1},{"message_id":2}]`)
+		return
+	}
+	writeResult(w, `{"message_id":1}`)
+}
+
+var assets = fstest.MapFS{
+	"assets/clip.mp4":   {Data: []byte("clip")},
+	"assets/thumb.jpg":  {Data: []byte("thumb")},
+	"assets/photo.jpg":  {Data: []byte("photo")},
+	"assets/smile.webp": {Data: []byte("smile")},
+}
+
+func TestFileFSMediaGroup(t *testing.T) {
+	server := &multipartServer{}
+	bot := newLocalBot(t, server.handle)
+
+	video := NewInputMediaVideo(FileFS{FS: assets, Path: "assets/clip.mp4"})
+	video.Thumb = FileFS{FS: assets, Path: "assets/thumb.jpg"}
+
+	if _, err := bot.SendMediaGroup(NewMediaGroup(1, []interface{}{
+		video,
+		NewInputMediaPhoto(FileFS{FS: assets, Path: "assets/photo.jpg", Name: "cover.jpg"}),
+	})); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"file-0":       "clip.mp4:clip",
+		"file-0-thumb": "thumb.jpg:thumb",
+		"file-1":       "cover.jpg:photo",
+	}
+	for field, file := range expected {
+		if server.files[field] != file {
+			t.Errorf("expected %s to be %s, got %q", field, file, server.files[field])
+		}
+	}
+
+	media := server.params["media"]
+	for _, attach := range []string{`"attach://file-0"`, `"attach://file-0-thumb"`, `"attach://file-1"`} {
+		if !strings.Contains(media, attach) {
+			t.Errorf("expected %s in media %s", attach, media)
+		}
+	}
+}
+
+func TestFileFSSticker(t *testing.T) {
+	server := &multipartServer{}
+	bot := newLocalBot(t, server.handle)
+
+	if _, err := bot.Request(CreateNewStickerSetConfig{
+		UserID: 1,
+		Name:   "smiles_by_bot",
+		Title:  "Smiles",
+		Stickers: []InputSticker{
+			{Sticker: FileID("existing"), Format: "static", EmojiList: []string{"🙂"}},
+			{Sticker: FileFS{FS: assets, Path: "assets/smile.webp"}, Format: "static", EmojiList: []string{"😀"}},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `[{"sticker":"existing","format":"static","emoji_list":["🙂"]},{"sticker":"attach://sticker-1","format":"static","emoji_list":["😀"]}]`
+	if server.params["stickers"] != expected {
+		t.Errorf("expected stickers %s, got %s", expected, server.params["stickers"])
+	}
+	if server.files["sticker-1"] != "smile.webp:smile" || len(server.files) != 1 {
+		t.Errorf("expected only the new sticker to be uploaded, got %v", server.files)
+	}
+
+	if _, err := bot.Send(NewPhoto(1, FileFS{FS: assets, Path: "assets/photo.jpg"})); err != nil {
+		t.Fatal(err)
+	}
+	if server.method != "sendPhoto" || server.files["photo"] != "photo.jpg:photo" {
+		t.Errorf("expected the photo to be uploaded, got %s with %v", server.method, server.files)
+	}
+}
+
+// failingReadCloser fails to be read and records whether it was closed.
+type failingReadCloser struct {
+	closed bool
+}
+
+func (r *failingReadCloser) Read(p []byte) (int, error) {
+	return 0, errors.New("read failed")
+}
+
+func (r *failingReadCloser) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestUploadClosesReaderOnError(t *testing.T) {
+	bot := newLocalBot(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		writeResult(w, `{"message_id":1}`)
+	})
+
+	reader := &failingReadCloser{}
+	if _, err := bot.Send(NewDocument(1, FileReader{Name: "file.txt", Reader: reader})); err == nil {
+		t.Fatal("expected the upload to fail")
+	}
+
+	if !reader.closed {
+		t.Error("expected the reader to be closed after a failed upload")
+	}
+}
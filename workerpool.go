@@ -0,0 +1,151 @@
+package tgbotapi
+
+import (
+	"context"
+	"strconv"
+	"sync"
+)
+
+// UpdateKeyFunc returns the key of an update. Updates with the same key are
+// handled one at a time, in the order they were received. Updates with an
+// empty key may be handled in any order.
+type UpdateKeyFunc func(update *Update) string
+
+// ChatKey is the default UpdateKeyFunc, keying updates by the ID of their
+// chat as returned by Update.FromChat. Updates without a chat, such as inline
+// queries, have no key.
+func ChatKey(update *Update) string {
+	chat := update.FromChat()
+	if chat == nil {
+		return ""
+	}
+
+	return strconv.FormatInt(chat.ID, 10)
+}
+
+// WorkerPool handles updates concurrently while keeping the order of the
+// updates of each chat. Updates from different chats are handled in parallel
+// by up to Workers goroutines, while updates from the same chat wait for the
+// previous ones to be handled.
+//
+// The pool holds at most Workers plus QueueSize updates that it read but
+// didn't handle yet. Once full, it stops reading updates until one is done,
+// applying backpressure to the poller, which stops getting updates once its
+// buffer is full.
+type WorkerPool struct {
+	// Workers is the maximum number of updates handled at the same time.
+	Workers int
+	// QueueSize is how many updates the pool holds in addition to Workers,
+	// waiting for earlier updates with the same key. It is Workers if zero.
+	QueueSize int
+	// Key returns the key of updates that must be handled in order. It is
+	// ChatKey if nil.
+	Key UpdateKeyFunc
+}
+
+// NewWorkerPool creates a WorkerPool handling up to workers updates at the
+// same time.
+func NewWorkerPool(workers int) *WorkerPool {
+	return &WorkerPool{Workers: workers}
+}
+
+// Run reads updates from the channel and handles them with handle, until the
+// channel is closed or ctx is done. It returns once all the updates it read
+// are handled.
+func (p *WorkerPool) Run(ctx context.Context, updates UpdatesChannel, handle func(update Update)) {
+	workers := max(p.Workers, 1)
+	queueSize := p.QueueSize
+	if queueSize <= 0 {
+		queueSize = workers
+	}
+	key := p.Key
+	if key == nil {
+		key = ChatKey
+	}
+
+	var (
+		// slots limits the updates read but not handled yet, running
+		// limits the ones being handled.
+		slots   = make(chan struct{}, workers+queueSize)
+		running = make(chan struct{}, workers)
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		// queues holds the updates waiting for each key being handled.
+		queues = make(map[string][]Update)
+	)
+
+	work := func(k string, update Update) {
+		defer wg.Done()
+
+		for {
+			running <- struct{}{}
+			handle(update)
+			<-running
+			<-slots
+
+			if k == "" {
+				return
+			}
+
+			mu.Lock()
+			queue := queues[k]
+			if len(queue) == 0 {
+				delete(queues, k)
+				mu.Unlock()
+				return
+			}
+			update, queues[k] = queue[0], queue[1:]
+			mu.Unlock()
+		}
+	}
+
+	defer wg.Wait()
+
+	for {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+
+		var (
+			update Update
+			ok     bool
+		)
+		select {
+		case update, ok = <-updates:
+		case <-ctx.Done():
+		}
+		if !ok {
+			return
+		}
+
+		k := key(&update)
+		if k != "" {
+			mu.Lock()
+			queue, busy := queues[k]
+			if busy {
+				queues[k] = append(queue, update)
+			} else {
+				// An empty queue marks the key as being handled.
+				queues[k] = nil
+			}
+			mu.Unlock()
+
+			if busy {
+				continue
+			}
+		}
+
+		wg.Add(1)
+		go work(k, update)
+	}
+}
+
+// RunConcurrently dispatches the updates from the channel with pool, like
+// Run but handling the updates of different chats concurrently.
+func (d *Dispatcher) RunConcurrently(ctx context.Context, updates UpdatesChannel, pool *WorkerPool) {
+	pool.Run(ctx, updates, func(update Update) {
+		d.dispatch(ctx, "", update)
+	})
+}
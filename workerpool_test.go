@@ -0,0 +1,199 @@
+package tgbotapi
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// chatUpdate returns a message update in the chat, whose text is n.
+func chatUpdate(chatID int64, n int) Update {
+	return Update{Message: &Message{Text: strconv.Itoa(n), Chat: &Chat{ID: chatID}}}
+}
+
+func TestWorkerPoolOrdering(t *testing.T) {
+	const chats, perChat, workers = 8, 20, 4
+
+	updates := make(chan Update)
+	go func() {
+		defer close(updates)
+		for n := 0; n < perChat; n++ {
+			for chat := int64(1); chat <= chats; chat++ {
+				updates <- chatUpdate(chat, n)
+			}
+		}
+	}()
+
+	var (
+		mu      sync.Mutex
+		handled = make(map[int64][]int)
+		active  = make(map[int64]bool)
+		running atomic.Int32
+		peak    atomic.Int32
+	)
+
+	NewWorkerPool(workers).Run(context.Background(), updates, func(update Update) {
+		chat := update.Message.Chat.ID
+
+		mu.Lock()
+		if active[chat] {
+			t.Errorf("chat %d handled concurrently", chat)
+		}
+		active[chat] = true
+		mu.Unlock()
+
+		n := running.Add(1)
+		for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+		}
+		time.Sleep(time.Millisecond)
+		running.Add(-1)
+
+		mu.Lock()
+		active[chat] = false
+		text, _ := strconv.Atoi(update.Message.Text)
+		handled[chat] = append(handled[chat], text)
+		mu.Unlock()
+	})
+
+	for chat := int64(1); chat <= chats; chat++ {
+		if len(handled[chat]) != perChat {
+			t.Fatalf("expected %d updates in chat %d, got %d", perChat, chat, len(handled[chat]))
+		}
+		for i, n := range handled[chat] {
+			if n != i {
+				t.Fatalf("expected the updates of chat %d in order, got %v", chat, handled[chat])
+			}
+		}
+	}
+
+	if p := peak.Load(); p < 2 || p > workers {
+		t.Errorf("expected between 2 and %d updates handled at once, got %d", workers, p)
+	}
+}
+
+func TestWorkerPoolBackpressure(t *testing.T) {
+	pool := &WorkerPool{Workers: 1, QueueSize: 1}
+	updates := make(chan Update)
+	release := make(chan struct{})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		pool.Run(context.Background(), updates, func(update Update) {
+			<-release
+		})
+	}()
+
+	sent := 0
+	for sent < 5 {
+		select {
+		case updates <- chatUpdate(1, sent):
+			sent++
+			continue
+		case <-time.After(50 * time.Millisecond):
+		}
+		break
+	}
+
+	if sent != 2 {
+		t.Errorf("expected the pool to read 2 updates before blocking, read %d", sent)
+	}
+
+	close(release)
+	close(updates)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected Run to return once the updates are handled")
+	}
+}
+
+func TestWorkerPoolUnkeyed(t *testing.T) {
+	updates := make(chan Update, 2)
+	updates <- Update{InlineQuery: &InlineQuery{ID: "a", From: &User{ID: 1}}}
+	updates <- Update{InlineQuery: &InlineQuery{ID: "b", From: &User{ID: 1}}}
+	close(updates)
+
+	// Inline queries have no chat, so both are handled at the same time.
+	var started sync.WaitGroup
+	started.Add(2)
+	both := make(chan struct{})
+	go func() {
+		started.Wait()
+		close(both)
+	}()
+
+	var concurrent atomic.Int32
+	NewWorkerPool(2).Run(context.Background(), updates, func(update Update) {
+		started.Done()
+		select {
+		case <-both:
+			concurrent.Add(1)
+		case <-time.After(time.Second):
+		}
+	})
+
+	if concurrent.Load() != 2 {
+		t.Error("expected updates without a key to be handled concurrently")
+	}
+}
+
+func TestWorkerPoolKey(t *testing.T) {
+	updates := make(chan Update, 3)
+	updates <- Update{InlineQuery: &InlineQuery{ID: "a", From: &User{ID: 1}}}
+	updates <- Update{InlineQuery: &InlineQuery{ID: "b", From: &User{ID: 2}}}
+	updates <- Update{InlineQuery: &InlineQuery{ID: "c", From: &User{ID: 1}}}
+	close(updates)
+
+	var (
+		mu      sync.Mutex
+		handled []string
+	)
+
+	pool := NewWorkerPool(4)
+	pool.Key = func(update *Update) string {
+		return strconv.FormatInt(update.SentFrom().ID, 10)
+	}
+	pool.Run(context.Background(), updates, func(update Update) {
+		if update.InlineQuery.ID == "a" {
+			time.Sleep(20 * time.Millisecond)
+		}
+
+		mu.Lock()
+		handled = append(handled, update.InlineQuery.ID)
+		mu.Unlock()
+	})
+
+	if got := strings.Join(handled, ","); got != "b,a,c" {
+		t.Errorf("expected the updates of user 1 in order after the one of user 2, got %s", got)
+	}
+}
+
+func TestDispatcherRunConcurrently(t *testing.T) {
+	d := NewDispatcher(&BotAPI{})
+
+	var handled atomic.Int32
+	d.Fallback(func(ctx context.Context, update Update) error {
+		handled.Add(1)
+		return nil
+	})
+
+	updates := make(chan Update)
+	go func() {
+		defer close(updates)
+		for n := 0; n < 10; n++ {
+			updates <- chatUpdate(int64(n%3), n)
+		}
+	}()
+
+	d.RunConcurrently(context.Background(), updates, NewWorkerPool(3))
+
+	if n := handled.Load(); n != 10 {
+		t.Errorf("expected 10 updates handled, got %d", n)
+	}
+}